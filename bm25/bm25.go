@@ -0,0 +1,247 @@
+// Package bm25 encodes text into sparse vectors using BM25 term weighting, for
+// use with Pinecone sparse and hybrid indexes.
+//
+// Tokens are hashed into sparse indices with MurmurHash3, so no vocabulary
+// needs to be stored. Documents are encoded with BM25 term-frequency
+// saturation and queries with inverse document frequency, so the dot product
+// of a query and a document vector is the document's BM25 score.
+//
+// Example:
+//
+//	enc := bm25.New()
+//	enc.Fit(corpus)
+//
+//	doc, err := enc.EncodeDocument("The quick brown fox")
+//	if err != nil {
+//	    // handle error
+//	}
+//	vectors := []*pinecone.Vector{{ID: "doc1", SparseValues: doc}}
+package bm25
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"slices"
+
+	pinecone "github.com/qhenkart/pinecone-lite"
+)
+
+// Default BM25 parameters.
+const (
+	DefaultK1 = 1.2
+	DefaultB  = 0.75
+)
+
+// ErrNotFitted is returned when encoding with an Encoder that has not been
+// fitted or loaded.
+var ErrNotFitted = errors.New("bm25: encoder has not been fitted")
+
+// Encoder holds BM25 parameters and corpus term statistics.
+type Encoder struct {
+	// K1 controls term-frequency saturation. New sets it to DefaultK1; zero
+	// weighs every present term equally, regardless of its frequency.
+	K1 float64
+
+	// B controls document-length normalization. New sets it to DefaultB; zero
+	// disables length normalization.
+	B float64
+
+	// Tokenize splits text into terms. Defaults to Tokenize. It is not
+	// serialized, so a custom tokenizer must be set again after loading.
+	Tokenize func(text string) []string
+
+	docFreq   map[uint32]int
+	numDocs   int
+	avgDocLen float64
+}
+
+// New returns an Encoder with the default parameters.
+func New() *Encoder {
+	return &Encoder{K1: DefaultK1, B: DefaultB, Tokenize: Tokenize}
+}
+
+// Fit computes document frequencies and the average document length from
+// corpus, replacing any previously fitted statistics.
+func (e *Encoder) Fit(corpus []string) {
+	e.docFreq = make(map[uint32]int)
+	e.numDocs = len(corpus)
+
+	total := 0
+	for _, doc := range corpus {
+		indices, tf := e.termFrequencies(doc)
+		for _, idx := range indices {
+			e.docFreq[idx]++
+		}
+		for _, n := range tf {
+			total += n
+		}
+	}
+
+	e.avgDocLen = 0
+	if e.numDocs > 0 {
+		e.avgDocLen = float64(total) / float64(e.numDocs)
+	}
+}
+
+// EncodeDocument encodes text for upserting as a vector's sparse values.
+func (e *Encoder) EncodeDocument(text string) (*pinecone.SparseValues, error) {
+	if e.numDocs == 0 || e.avgDocLen == 0 {
+		return nil, ErrNotFitted
+	}
+
+	indices, tf := e.termFrequencies(text)
+	docLen := 0
+	for _, n := range tf {
+		docLen += n
+	}
+
+	norm := e.K1 * (1 - e.B + e.B*float64(docLen)/e.avgDocLen)
+
+	sv := &pinecone.SparseValues{
+		Indices: indices,
+		Values:  make([]float32, len(indices)),
+	}
+	for i, n := range tf {
+		sv.Values[i] = float32(float64(n) / (norm + float64(n)))
+	}
+	return sv, nil
+}
+
+// EncodeDocuments encodes each text as with EncodeDocument.
+func (e *Encoder) EncodeDocuments(texts []string) ([]*pinecone.SparseValues, error) {
+	out := make([]*pinecone.SparseValues, len(texts))
+	for i, text := range texts {
+		sv, err := e.EncodeDocument(text)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = sv
+	}
+	return out, nil
+}
+
+// EncodeQuery encodes text for use as a query's sparse vector. Each term is
+// weighted by its inverse document frequency, normalized to sum to one.
+func (e *Encoder) EncodeQuery(text string) (*pinecone.SparseValues, error) {
+	if e.numDocs == 0 {
+		return nil, ErrNotFitted
+	}
+
+	indices, _ := e.termFrequencies(text)
+	sv := &pinecone.SparseValues{
+		Indices: indices,
+		Values:  make([]float32, len(indices)),
+	}
+
+	idf := make([]float64, len(indices))
+	sum := 0.0
+	for i, idx := range indices {
+		df, ok := e.docFreq[idx]
+		if !ok {
+			df = 1
+		}
+		idf[i] = math.Log((float64(e.numDocs) + 1) / (float64(df) + 0.5))
+		sum += idf[i]
+	}
+	for i, w := range idf {
+		if sum != 0 {
+			w /= sum
+		}
+		sv.Values[i] = float32(w)
+	}
+	return sv, nil
+}
+
+// termFrequencies tokenizes text and returns the sorted distinct token
+// indices alongside their counts.
+func (e *Encoder) termFrequencies(text string) ([]uint32, []int) {
+	tokenize := e.Tokenize
+	if tokenize == nil {
+		tokenize = Tokenize
+	}
+
+	counts := make(map[uint32]int)
+	for _, tok := range tokenize(text) {
+		counts[murmur3([]byte(tok))]++
+	}
+
+	indices := make([]uint32, 0, len(counts))
+	for idx := range counts {
+		indices = append(indices, idx)
+	}
+	slices.Sort(indices)
+
+	tf := make([]int, len(indices))
+	for i, idx := range indices {
+		tf[i] = counts[idx]
+	}
+	return indices, tf
+}
+
+// encoderParams is the serialized form of an Encoder.
+type encoderParams struct {
+	K1        float64 `json:"k1"`
+	B         float64 `json:"b"`
+	AvgDocLen float64 `json:"avgdl"`
+	NumDocs   int     `json:"n_docs"`
+	DocFreq   struct {
+		Indices []uint32 `json:"indices"`
+		Values  []int    `json:"values"`
+	} `json:"doc_freq"`
+}
+
+// MarshalJSON encodes the encoder's parameters and fitted statistics.
+func (e *Encoder) MarshalJSON() ([]byte, error) {
+	var p encoderParams
+	p.K1, p.B = e.K1, e.B
+	p.AvgDocLen = e.avgDocLen
+	p.NumDocs = e.numDocs
+
+	p.DocFreq.Indices = make([]uint32, 0, len(e.docFreq))
+	for idx := range e.docFreq {
+		p.DocFreq.Indices = append(p.DocFreq.Indices, idx)
+	}
+	slices.Sort(p.DocFreq.Indices)
+	p.DocFreq.Values = make([]int, len(p.DocFreq.Indices))
+	for i, idx := range p.DocFreq.Indices {
+		p.DocFreq.Values[i] = e.docFreq[idx]
+	}
+
+	return json.Marshal(p)
+}
+
+// UnmarshalJSON restores parameters and statistics written by MarshalJSON.
+func (e *Encoder) UnmarshalJSON(data []byte) error {
+	var p encoderParams
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	if len(p.DocFreq.Indices) != len(p.DocFreq.Values) {
+		return errors.New("bm25: doc_freq indices and values differ in length")
+	}
+
+	e.K1, e.B = p.K1, p.B
+	e.avgDocLen = p.AvgDocLen
+	e.numDocs = p.NumDocs
+	e.docFreq = make(map[uint32]int, len(p.DocFreq.Indices))
+	for i, idx := range p.DocFreq.Indices {
+		e.docFreq[idx] = p.DocFreq.Values[i]
+	}
+	return nil
+}
+
+// Save writes the encoder's parameters to w as JSON.
+func (e *Encoder) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(e)
+}
+
+// Load reads an encoder saved with Save.
+func Load(r io.Reader) (*Encoder, error) {
+	e := New()
+	if err := json.NewDecoder(r).Decode(e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
@@ -0,0 +1,114 @@
+package bm25
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+var corpus = []string{
+	"the quick brown fox jumps over the lazy dog",
+	"the lazy dog sleeps all day",
+	"a quick brown dog outpaces a quick fox",
+}
+
+func TestEncoder(t *testing.T) {
+	t.Run("not_fitted", func(t *testing.T) {
+		if _, err := New().EncodeQuery("fox"); !errors.Is(err, ErrNotFitted) {
+			t.Fatalf("expected ErrNotFitted, got %v", err)
+		}
+	})
+
+	t.Run("document_weights", func(t *testing.T) {
+		enc := New()
+		enc.Fit(corpus)
+
+		sv, err := enc.EncodeDocument("quick quick fox")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(sv.Indices) != 2 || len(sv.Values) != 2 {
+			t.Fatalf("expected 2 terms, got %+v", sv)
+		}
+
+		quick := murmur3([]byte("quick"))
+		for i, idx := range sv.Indices {
+			if i > 0 && sv.Indices[i-1] >= idx {
+				t.Errorf("expected sorted indices, got %v", sv.Indices)
+			}
+			// avgdl is 16/3 and the document has 3 tokens.
+			norm := DefaultK1 * (1 - DefaultB + DefaultB*3/(16.0/3))
+			tf := 1.0
+			if idx == quick {
+				tf = 2
+			}
+			if want := tf / (norm + tf); math.Abs(float64(sv.Values[i])-want) > 1e-6 {
+				t.Errorf("index %d: expected %v, got %v", idx, want, sv.Values[i])
+			}
+		}
+	})
+
+	t.Run("zero_b_disables_length_normalization", func(t *testing.T) {
+		enc := New()
+		enc.B = 0
+		enc.Fit(corpus)
+
+		short, _ := enc.EncodeDocument("fox")
+		long, _ := enc.EncodeDocument("fox jumps over the lazy sleeping dog")
+		want := float32(1 / (DefaultK1 + 1))
+		if short.Values[0] != want || long.Values[slices.Index(long.Indices, short.Indices[0])] != want {
+			t.Errorf("expected length-independent weight %v, got %v and %v", want, short.Values, long.Values)
+		}
+	})
+
+	t.Run("query_weights_normalized", func(t *testing.T) {
+		enc := New()
+		enc.Fit(corpus)
+
+		sv, err := enc.EncodeQuery("sleeps dog")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		sum := float32(0)
+		weights := map[uint32]float32{}
+		for i, idx := range sv.Indices {
+			sum += sv.Values[i]
+			weights[idx] = sv.Values[i]
+		}
+		if math.Abs(float64(sum)-1) > 1e-6 {
+			t.Errorf("expected weights to sum to 1, got %v", sum)
+		}
+		if weights[murmur3([]byte("sleeps"))] <= weights[murmur3([]byte("dog"))] {
+			t.Errorf("expected rare term to outweigh common term: %v", weights)
+		}
+	})
+
+	t.Run("save_and_load", func(t *testing.T) {
+		enc := New()
+		enc.K1 = 1.5
+		enc.Fit(corpus)
+
+		var buf bytes.Buffer
+		if err := enc.Save(&buf); err != nil {
+			t.Fatalf("save failed: %v", err)
+		}
+		loaded, err := Load(&buf)
+		if err != nil {
+			t.Fatalf("load failed: %v", err)
+		}
+
+		want, _ := enc.EncodeQuery("lazy fox")
+		got, _ := loaded.EncodeQuery("lazy fox")
+		if loaded.K1 != 1.5 || loaded.numDocs != 3 {
+			t.Errorf("unexpected loaded params: %+v", loaded)
+		}
+		for i := range want.Values {
+			if got.Indices[i] != want.Indices[i] || got.Values[i] != want.Values[i] {
+				t.Fatalf("expected %+v, got %+v", want, got)
+			}
+		}
+	})
+}
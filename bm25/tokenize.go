@@ -0,0 +1,42 @@
+package bm25
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopwords is the English stopword list removed by Tokenize.
+var stopwords = map[string]struct{}{}
+
+func init() {
+	for _, w := range strings.Fields(`
+		a about above after again against all am an and any are as at
+		be because been before being below between both but by
+		can could did do does doing down during each few for from further
+		had has have having he her here hers herself him himself his how
+		i if in into is it its itself just me more most my myself
+		no nor not now of off on once only or other our ours ourselves out over own
+		s same she should so some such t than that the their theirs them themselves then
+		there these they this those through to too under until up very
+		was we were what when where which while who whom why will with would
+		you your yours yourself yourselves`) {
+		stopwords[w] = struct{}{}
+	}
+}
+
+// Tokenize lowercases text, splits it into runs of letters and digits and
+// drops English stopwords. It is the default tokenizer used by Encoder.
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := fields[:0]
+	for _, f := range fields {
+		if _, ok := stopwords[f]; ok {
+			continue
+		}
+		tokens = append(tokens, f)
+	}
+	return tokens
+}
@@ -0,0 +1,27 @@
+package bm25
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("The Quick, brown fox -- jumps over the lazy dog's 2nd bone!")
+	want := []string{"quick", "brown", "fox", "jumps", "lazy", "dog", "2nd", "bone"}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestMurmur3(t *testing.T) {
+	cases := map[string]uint32{
+		"":      0,
+		"hello": 613153351,
+		"The quick brown fox jumps over the lazy dog": 776992547,
+	}
+	for in, want := range cases {
+		if got := murmur3([]byte(in)); got != want {
+			t.Errorf("murmur3(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
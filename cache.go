@@ -0,0 +1,231 @@
+package pinecone
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+)
+
+// QueryCache is an LRU cache of query responses with a TTL. Attach it to a
+// client with Client.Cache; writes issued through the same client invalidate
+// the cached queries of the namespace they touch. Responses served from the
+// cache report zero read units, since no query was billed.
+//
+// A QueryCache is safe for concurrent use and may be shared by several clients
+// of the same index.
+type QueryCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	ll      *list.List
+	entries map[[sha256.Size]byte]*list.Element
+
+	// generations counts invalidations per namespace, so a response fetched
+	// before a write is not stored after it.
+	generations map[string]uint64
+
+	stats CacheStats
+
+	now func() time.Time
+}
+
+// CacheStats reports QueryCache activity.
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   int
+}
+
+type cacheEntry struct {
+	key       [sha256.Size]byte
+	namespace string
+	resp      *QueryByVectorResponse
+	expires   time.Time
+}
+
+// NewQueryCache returns a cache holding at most size responses, each for at
+// most ttl. A zero ttl keeps entries until they are evicted or invalidated.
+func NewQueryCache(size int, ttl time.Duration) *QueryCache {
+	if size <= 0 {
+		size = 1
+	}
+	return &QueryCache{
+		size:        size,
+		ttl:         ttl,
+		ll:          list.New(),
+		entries:     make(map[[sha256.Size]byte]*list.Element),
+		generations: make(map[string]uint64),
+		now:         time.Now,
+	}
+}
+
+// Stats returns a snapshot of the cache counters.
+func (qc *QueryCache) Stats() CacheStats {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	s := qc.stats
+	s.Entries = qc.ll.Len()
+	return s
+}
+
+// Invalidate removes every cached response for namespace.
+func (qc *QueryCache) Invalidate(namespace string) {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	qc.generations[namespace]++
+	for e := qc.ll.Front(); e != nil; {
+		next := e.Next()
+		if ent := e.Value.(*cacheEntry); ent.namespace == namespace {
+			qc.remove(e)
+		}
+		e = next
+	}
+}
+
+// Purge removes every cached response.
+func (qc *QueryCache) Purge() {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	for ns := range qc.generations {
+		qc.generations[ns]++
+	}
+	qc.ll.Init()
+	qc.entries = make(map[[sha256.Size]byte]*list.Element)
+}
+
+// get returns a copy of the cached response for key, and the namespace
+// generation to pass to put on a miss.
+func (qc *QueryCache) get(key [sha256.Size]byte, namespace string) (*QueryByVectorResponse, uint64) {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	if e, ok := qc.entries[key]; ok {
+		ent := e.Value.(*cacheEntry)
+		if ent.expires.IsZero() || qc.now().Before(ent.expires) {
+			qc.stats.Hits++
+			qc.ll.MoveToFront(e)
+			resp := cloneResponse(ent.resp)
+			resp.Usage = ReadUsage{}
+			return resp, 0
+		}
+		qc.remove(e)
+	}
+	qc.stats.Misses++
+	return nil, qc.generations[namespace]
+}
+
+// put stores resp unless namespace was invalidated since generation gen.
+func (qc *QueryCache) put(key [sha256.Size]byte, namespace string, gen uint64, resp *QueryByVectorResponse) {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	if qc.generations[namespace] != gen {
+		return
+	}
+
+	ent := &cacheEntry{key: key, namespace: namespace, resp: cloneResponse(resp)}
+	if qc.ttl > 0 {
+		ent.expires = qc.now().Add(qc.ttl)
+	}
+
+	if e, ok := qc.entries[key]; ok {
+		e.Value = ent
+		qc.ll.MoveToFront(e)
+		return
+	}
+	qc.entries[key] = qc.ll.PushFront(ent)
+
+	for qc.ll.Len() > qc.size {
+		qc.remove(qc.ll.Back())
+		qc.stats.Evictions++
+	}
+}
+
+// remove deletes e from the cache. The caller must hold qc.mu.
+func (qc *QueryCache) remove(e *list.Element) {
+	qc.ll.Remove(e)
+	delete(qc.entries, e.Value.(*cacheEntry).key)
+}
+
+// queryCacheKey hashes every field of req that affects the response.
+func queryCacheKey(req *QueryByVectorRequest) ([sha256.Size]byte, error) {
+	b, err := json.Marshal(struct {
+		Vector          []float32      `json:"vector"`
+		SparseVector    *SparseValues  `json:"sparseVector"`
+		TopK            int            `json:"topK"`
+		Namespace       string         `json:"namespace"`
+		Filter          map[string]any `json:"filter"`
+		IncludeValues   bool           `json:"includeValues"`
+		IncludeMetadata bool           `json:"includeMetadata"`
+		MinScore        *float64       `json:"minScore"`
+	}{
+		req.Vector, req.SparseVector, req.TopK, req.Namespace, req.Filter,
+		req.IncludeValues, req.IncludeMetadata, req.MinScore,
+	})
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(b), nil
+}
+
+// cloneResponse deep-copies resp so callers cannot modify cached matches,
+// their values or their metadata.
+func cloneResponse(resp *QueryByVectorResponse) *QueryByVectorResponse {
+	out := *resp
+	out.Matches = make([]MatchResult, len(resp.Matches))
+	for i, m := range resp.Matches {
+		m.Values = slices.Clone(m.Values)
+		m.SparseValues = m.SparseValues.clone()
+		if m.Metadata != nil {
+			m.Metadata = cloneValue(m.Metadata).(map[string]any)
+		}
+		out.Matches[i] = m
+	}
+	return &out
+}
+
+// cloneValue deep-copies a decoded JSON value or a filter built from maps and
+// slices.
+func cloneValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, x := range v {
+			out[k] = cloneValue(x)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, x := range v {
+			out[i] = cloneValue(x)
+		}
+		return out
+	case []map[string]any:
+		out := make([]map[string]any, len(v))
+		for i, x := range v {
+			out[i] = cloneValue(x).(map[string]any)
+		}
+		return out
+	case []string:
+		return slices.Clone(v)
+	case []float64:
+		return slices.Clone(v)
+	case []int:
+		return slices.Clone(v)
+	}
+	return v
+}
+
+// invalidate drops cached queries for namespace after a write.
+func (c *Client) invalidate(namespace string) {
+	if c.Cache != nil {
+		c.Cache.Invalidate(namespace)
+	}
+}
@@ -0,0 +1,148 @@
+package pinecone
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestQueryCache(t *testing.T) {
+	newServer := func(queries *int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/query":
+				*queries++
+				w.Write([]byte(`{"matches":[{"id":"a","score":0.9,"values":[1],"metadata":{"tags":["x"]}}],"namespace":"ns","usage":{"readUnits":5}}`))
+			case "/vectors/upsert":
+				w.Write([]byte(`{"upsertedCount":1}`))
+			default:
+				w.WriteHeader(http.StatusOK)
+			}
+		}))
+	}
+	query := func(ns string) *QueryByVectorRequest {
+		return &QueryByVectorRequest{Vector: []float32{0.1, 0.2}, TopK: 1, Namespace: ns}
+	}
+
+	t.Run("hits_and_misses", func(t *testing.T) {
+		var queries int
+		s := newServer(&queries)
+		defer s.Close()
+
+		client := NewClient(s.URL, "key")
+		client.Cache = NewQueryCache(10, time.Minute)
+		ctx := context.Background()
+
+		first, _ := client.QueryByVector(ctx, query("ns"))
+		first.Matches[0].ID = "mutated"
+		first.Matches[0].Values[0] = 99
+		first.Matches[0].Metadata["tags"].([]any)[0] = "mutated"
+		second, err := client.QueryByVector(ctx, query("ns"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if m := second.Matches[0]; m.ID != "a" || m.Values[0] != 1 || m.Metadata["tags"].([]any)[0] != "x" {
+			t.Errorf("expected cached response to be isolated from caller changes, got %+v", m)
+		}
+		if first.Usage.ReadUnits != 5 || second.Usage.ReadUnits != 0 {
+			t.Errorf("expected read units only on the miss, got %d and %d", first.Usage.ReadUnits, second.Usage.ReadUnits)
+		}
+		client.QueryByVector(ctx, &QueryByVectorRequest{Vector: []float32{0.1, 0.2}, TopK: 2, Namespace: "ns"})
+
+		if queries != 2 {
+			t.Errorf("expected 2 requests, got %d", queries)
+		}
+		if stats := client.Cache.Stats(); stats.Hits != 1 || stats.Misses != 2 || stats.Entries != 2 {
+			t.Errorf("unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("invalidated_by_writes", func(t *testing.T) {
+		var queries int
+		s := newServer(&queries)
+		defer s.Close()
+
+		client := NewClient(s.URL, "key")
+		client.Cache = NewQueryCache(10, 0)
+		ctx := context.Background()
+
+		writes := []func(){
+			func() { client.UpsertVectors(ctx, []*Vector{{ID: "a", Values: []float32{1}}}, "ns") },
+			func() { client.DeleteVectorsByID(ctx, []string{"a"}, "ns") },
+			func() { client.DeleteVectorsByMetadata(ctx, "ns", map[string]any{"genre": "drama"}) },
+			func() { client.DeleteAllRecordsInNamespace(ctx, "ns") },
+		}
+		for i, write := range writes {
+			client.QueryByVector(ctx, query("ns"))
+			client.QueryByVector(ctx, query("other"))
+			write()
+			client.QueryByVector(ctx, query("ns"))
+			client.QueryByVector(ctx, query("other"))
+
+			// "other" is fetched once overall; "ns" once initially and once per write.
+			if want := 2 + i + 1; queries != want {
+				t.Fatalf("write %d: expected %d requests, got %d", i, want, queries)
+			}
+		}
+	})
+
+	t.Run("ttl_expiry", func(t *testing.T) {
+		var queries int
+		s := newServer(&queries)
+		defer s.Close()
+
+		now := time.Unix(0, 0)
+		client := NewClient(s.URL, "key")
+		client.Cache = NewQueryCache(10, time.Minute)
+		client.Cache.now = func() time.Time { return now }
+
+		client.QueryByVector(context.Background(), query("ns"))
+		now = now.Add(2 * time.Minute)
+		client.QueryByVector(context.Background(), query("ns"))
+
+		if queries != 2 {
+			t.Errorf("expected expired entry to be refetched, got %d requests", queries)
+		}
+	})
+
+	t.Run("lru_eviction", func(t *testing.T) {
+		qc := NewQueryCache(2, 0)
+		resp := &QueryByVectorResponse{}
+		keys := make([][32]byte, 3)
+		for i := range keys {
+			keys[i], _ = queryCacheKey(&QueryByVectorRequest{TopK: i})
+		}
+
+		for _, k := range keys[:2] {
+			_, gen := qc.get(k, "ns")
+			qc.put(k, "ns", gen, resp)
+		}
+		qc.get(keys[0], "ns")
+		qc.put(keys[2], "ns", 0, resp)
+
+		if got, _ := qc.get(keys[1], "ns"); got != nil {
+			t.Error("expected least recently used entry to be evicted")
+		}
+		if got, _ := qc.get(keys[0], "ns"); got == nil {
+			t.Error("expected recently used entry to be kept")
+		}
+		if qc.Stats().Evictions != 1 {
+			t.Errorf("expected 1 eviction, got %d", qc.Stats().Evictions)
+		}
+	})
+
+	t.Run("stale_put_dropped", func(t *testing.T) {
+		qc := NewQueryCache(2, 0)
+		key, _ := queryCacheKey(&QueryByVectorRequest{})
+
+		_, gen := qc.get(key, "ns")
+		qc.Invalidate("ns")
+		qc.put(key, "ns", gen, &QueryByVectorResponse{})
+
+		if qc.Stats().Entries != 0 {
+			t.Error("expected response fetched before invalidation to be dropped")
+		}
+	})
+}
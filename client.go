@@ -1,9 +1,7 @@
 package pinecone
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"io"
 	"net/http"
 	"strings"
@@ -19,6 +17,38 @@ type Client struct {
 
 	// HTTPClient is the underlying HTTP client used for requests. Defaults to http.DefaultClient.
 	HTTPClient *http.Client
+
+	// Metric is the index metric, used wherever scores are compared on the
+	// client: MinScore, QueryMMR and the merging of fanned-out queries. If
+	// empty, the metric of Validator is used, and failing that cosine, so it
+	// must be set for euclidean indexes, where lower scores are better.
+	Metric Metric
+
+	// Validator, if set, checks vectors against the index dimension and metric
+	// before upserts and queries are sent. See DiscoverValidator.
+	Validator *VectorValidator
+
+	// MetadataValidator, if set, checks vector metadata against Pinecone's size
+	// and type limits before upserts are sent.
+	MetadataValidator *MetadataValidator
+
+	// GzipThreshold, when positive, gzip-compresses request bodies of at least
+	// this many bytes and sends them with Content-Encoding: gzip.
+	GzipThreshold int
+
+	// AcceptGzip requests gzip-compressed responses and decompresses them
+	// transparently. This mostly benefits queries with IncludeValues and
+	// fetches of high-dimensional vectors.
+	AcceptGzip bool
+
+	// MaxConcurrency limits the number of requests issued concurrently by
+	// fan-out helpers such as QueryNamespaces. Defaults to 8.
+	MaxConcurrency int
+
+	// Cache, if set, serves repeated QueryByVector calls from memory. Upserts
+	// and deletes issued through this client invalidate the cached queries of
+	// their namespace. See NewQueryCache.
+	Cache *QueryCache
 }
 
 // NewClient creates and returns a new Pinecone REST client.
@@ -32,24 +62,85 @@ func NewClient(indexURL, apiKey string) *Client {
 
 // do sends an HTTP request to the Pinecone API with proper headers and optional JSON body.
 // It returns the raw HTTP response or an error.
+//
+// Request bodies are encoded into pooled buffers and gzip-compressed when they
+// reach c.GzipThreshold. When c.AcceptGzip is set, compressed responses are
+// decompressed before they are returned.
 func (c *Client) do(ctx context.Context, method, path string, body any) (*http.Response, error) {
-	var buf io.Reader
+	var encoded *pooledBody
+	var gzipped bool
 	if body != nil {
-		b, err := json.Marshal(body)
-		if err != nil {
+		var err error
+		if encoded, gzipped, err = c.encodeBody(body); err != nil {
 			return nil, err
 		}
-		buf = bytes.NewReader(b)
+		// encoded stays open until the request completes. The transport reads
+		// clones of it, so GetBody can replay the buffer for redirects and
+		// retries after the transport has closed the first body.
+		defer encoded.Close()
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, c.IndexURL+path, buf)
+	req, err := http.NewRequestWithContext(ctx, method, c.IndexURL+path, nil)
 	if err != nil {
 		return nil, err
 	}
+	if encoded != nil {
+		req.GetBody = func() (io.ReadCloser, error) {
+			return encoded.clone()
+		}
+		if req.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+		req.ContentLength = int64(encoded.Len())
+	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Api-Key", c.APIKey)
 	req.Header.Set("X-Pinecone-API-Version", "2025-04")
+	if gzipped {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	if c.AcceptGzip {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
 
-	return c.HTTPClient.Do(req)
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if c.AcceptGzip && resp.Header.Get("Content-Encoding") == "gzip" {
+		resp.Body = &gzipBody{body: resp.Body}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+	}
+	return resp, nil
+}
+
+// encodeBody encodes body as JSON into a pooled buffer, compressing it when it
+// reaches c.GzipThreshold. It reports whether the result is gzip-compressed.
+//
+// Bodies implementing jsonAppender are encoded directly instead of going
+// through json.Marshal.
+func (c *Client) encodeBody(body any) (*pooledBody, bool, error) {
+	a, ok := body.(jsonAppender)
+	if !ok {
+		a = jsonValue{body}
+	}
+
+	pb, err := newPooledBody(a)
+	if err != nil {
+		return nil, false, err
+	}
+	if c.GzipThreshold <= 0 || pb.Len() < c.GzipThreshold {
+		return pb, false, nil
+	}
+
+	defer pb.Close()
+	zb, err := newGzipBody(pb.bytes())
+	if err != nil {
+		return nil, false, err
+	}
+	return zb, true, nil
 }
@@ -0,0 +1,535 @@
+// Command pinecone-lite performs everyday operations against a Pinecone index.
+//
+// Usage:
+//
+//	pinecone-lite <command> [flags] [args]
+//
+// Commands:
+//
+//	query    similarity search with a dense vector
+//	upsert   upsert vectors from a JSONL, CSV or fvecs file
+//	list     list vector IDs in a namespace
+//	fetch    fetch vectors by ID
+//	delete   delete vectors by ID, by metadata filter or a whole namespace
+//	stats    describe index statistics
+//	export   write every vector in a namespace as JSONL
+//
+// The index URL and API key are read from the -url and -api-key flags, falling
+// back to the PINECONE_INDEX_URL and PINECONE_API_KEY environment variables.
+// Destructive deletes (-filter and -all) require -yes.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	pinecone "github.com/qhenkart/pinecone-lite"
+)
+
+// exportBatchSize is the number of IDs fetched per request by export.
+const exportBatchSize = 100
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line in args and returns the process exit code.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+
+	commands := map[string]func(context.Context, *cli, []string) error{
+		"query":  cmdQuery,
+		"upsert": cmdUpsert,
+		"list":   cmdList,
+		"fetch":  cmdFetch,
+		"delete": cmdDelete,
+		"stats":  cmdStats,
+		"export": cmdExport,
+	}
+
+	name := args[0]
+	cmd, ok := commands[name]
+	if !ok {
+		if name != "-h" && name != "-help" && name != "help" {
+			fmt.Fprintf(stderr, "unknown command %q\n", name)
+		}
+		usage(stderr)
+		return 2
+	}
+
+	c := &cli{name: name, stdout: stdout, stderr: stderr}
+	if err := cmd(ctx, c, args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 2
+		}
+		fmt.Fprintf(stderr, "pinecone-lite %s: %v\n", name, err)
+		return 1
+	}
+	return 0
+}
+
+func usage(w io.Writer) {
+	fmt.Fprint(w, `usage: pinecone-lite <command> [flags] [args]
+
+commands:
+  query    similarity search with a dense vector
+  upsert   upsert vectors from a JSONL, CSV or fvecs file
+  list     list vector IDs in a namespace
+  fetch    fetch vectors by ID
+  delete   delete vectors by ID, by metadata filter or a whole namespace
+  stats    describe index statistics
+  export   write every vector in a namespace as JSONL
+
+Run "pinecone-lite <command> -h" for command flags.
+`)
+}
+
+// cli holds the state shared by every command.
+type cli struct {
+	name   string
+	stdout io.Writer
+	stderr io.Writer
+
+	url    string
+	apiKey string
+	output string
+}
+
+// flags returns a FlagSet for the command with the connection and output
+// flags already registered.
+func (c *cli) flags() *flag.FlagSet {
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.StringVar(&c.url, "url", os.Getenv("PINECONE_INDEX_URL"), "index URL (env PINECONE_INDEX_URL)")
+	fs.StringVar(&c.apiKey, "api-key", os.Getenv("PINECONE_API_KEY"), "API key (env PINECONE_API_KEY)")
+	fs.StringVar(&c.output, "o", "table", "output format: table or json")
+	return fs
+}
+
+// client validates the connection flags and returns a client.
+func (c *cli) client() (*pinecone.Client, error) {
+	if c.url == "" {
+		return nil, errors.New("missing index URL: set -url or PINECONE_INDEX_URL")
+	}
+	if c.apiKey == "" {
+		return nil, errors.New("missing API key: set -api-key or PINECONE_API_KEY")
+	}
+	if c.output != "table" && c.output != "json" {
+		return nil, fmt.Errorf("unknown output format %q", c.output)
+	}
+	return pinecone.NewClient(c.url, c.apiKey), nil
+}
+
+// print writes v as indented JSON when -o json is set, and otherwise calls
+// table with a tabwriter.
+func (c *cli) print(v any, table func(w io.Writer)) error {
+	if c.output == "json" {
+		enc := json.NewEncoder(c.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	tw := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	table(tw)
+	return tw.Flush()
+}
+
+// parseFilter decodes a JSON metadata filter flag value.
+func parseFilter(s string) (map[string]any, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var filter map[string]any
+	if err := json.Unmarshal([]byte(s), &filter); err != nil {
+		return nil, fmt.Errorf("invalid -filter: %w", err)
+	}
+	return filter, nil
+}
+
+// formatMetadata renders metadata as compact JSON for table output.
+func formatMetadata(m map[string]any) string {
+	if len(m) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(m)
+	return string(b)
+}
+
+func cmdQuery(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags()
+	namespace := fs.String("namespace", "", "namespace to query")
+	vector := fs.String("vector", "", "query vector as a JSON array")
+	topK := fs.Int("top-k", 10, "number of matches to return")
+	filter := fs.String("filter", "", "metadata filter as JSON")
+	values := fs.Bool("include-values", false, "include vector values in matches")
+	metadata := fs.Bool("include-metadata", true, "include metadata in matches")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+	if *vector == "" {
+		return errors.New("missing -vector")
+	}
+
+	req := &pinecone.QueryByVectorRequest{
+		TopK:            *topK,
+		Namespace:       *namespace,
+		IncludeValues:   *values,
+		IncludeMetadata: *metadata,
+	}
+	if err := json.Unmarshal([]byte(*vector), &req.Vector); err != nil {
+		return fmt.Errorf("invalid -vector: %w", err)
+	}
+	if req.Filter, err = parseFilter(*filter); err != nil {
+		return err
+	}
+
+	resp, err := client.QueryByVector(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return c.print(resp, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tSCORE\tMETADATA")
+		for _, m := range resp.Matches {
+			fmt.Fprintf(w, "%s\t%.6f\t%s\n", m.ID, m.Score, formatMetadata(m.Metadata))
+		}
+	})
+}
+
+func cmdUpsert(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags()
+	namespace := fs.String("namespace", "", "namespace to upsert into")
+	file := fs.String("file", "", "input file, or - for stdin")
+	format := fs.String("format", "", "input format: jsonl, csv or fvecs (default from file extension)")
+	batch := fs.Int("batch-size", 0, "vectors per upsert request (default 100)")
+	dryRun := fs.Bool("dry-run", false, "validate the input without upserting")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("missing -file")
+	}
+
+	f, err := parseFormat(*format, *file)
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+	if *file != "-" {
+		fh, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer fh.Close()
+		r = fh
+	}
+
+	report, err := client.ImportFile(ctx, *namespace, r, f, &pinecone.ImportOptions{
+		BatchSize: *batch,
+		DryRun:    *dryRun,
+	})
+	if report != nil {
+		for _, lerr := range report.Errors {
+			fmt.Fprintf(c.stderr, "%s: %v\n", *file, lerr)
+		}
+		if perr := c.print(report, func(w io.Writer) {
+			fmt.Fprintln(w, "PARSED\tUPSERTED\tERRORS")
+			fmt.Fprintf(w, "%d\t%d\t%d\n", report.Parsed, report.Upserted, len(report.Errors))
+		}); perr != nil && err == nil {
+			err = perr
+		}
+	}
+	return err
+}
+
+// parseFormat resolves the -format flag, inferring it from the file extension
+// when empty.
+func parseFormat(format, file string) (pinecone.ImportFormat, error) {
+	if format == "" {
+		switch {
+		case strings.HasSuffix(file, ".csv"):
+			format = "csv"
+		case strings.HasSuffix(file, ".fvecs"):
+			format = "fvecs"
+		default:
+			format = "jsonl"
+		}
+	}
+
+	switch format {
+	case "jsonl":
+		return pinecone.FormatJSONL, nil
+	case "csv":
+		return pinecone.FormatCSV, nil
+	case "fvecs":
+		return pinecone.FormatFvecs, nil
+	}
+	return 0, fmt.Errorf("unknown format %q", format)
+}
+
+func cmdList(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags()
+	namespace := fs.String("namespace", "", "namespace to list")
+	prefix := fs.String("prefix", "", "only list IDs with this prefix")
+	limit := fs.Int("limit", 100, "maximum IDs per page")
+	token := fs.String("token", "", "pagination token from a previous call")
+	all := fs.Bool("all", false, "follow pagination until every ID is listed")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	var ids []string
+	next := *token
+	for {
+		page, tok, err := client.ListVectorIDs(ctx, *namespace, *prefix, *limit, next)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, page...)
+		next = tok
+		if !*all || next == "" {
+			break
+		}
+	}
+
+	result := struct {
+		IDs  []string `json:"ids"`
+		Next string   `json:"next,omitempty"`
+	}{ids, next}
+
+	return c.print(result, func(w io.Writer) {
+		for _, id := range ids {
+			fmt.Fprintln(w, id)
+		}
+		if next != "" {
+			fmt.Fprintf(c.stderr, "next token: %s\n", next)
+		}
+	})
+}
+
+func cmdFetch(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags()
+	namespace := fs.String("namespace", "", "namespace to fetch from")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("no IDs given")
+	}
+
+	resp, err := client.FetchVectors(ctx, fs.Args(), *namespace)
+	if err != nil {
+		return err
+	}
+
+	return c.print(resp, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tDIMENSION\tMETADATA")
+		for _, id := range fs.Args() {
+			v, ok := resp.Vectors[id]
+			if !ok {
+				fmt.Fprintf(w, "%s\t-\tnot found\n", id)
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%d\t%s\n", v.ID, len(v.Values), formatMetadata(v.Metadata))
+		}
+	})
+}
+
+func cmdDelete(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags()
+	namespace := fs.String("namespace", "", "namespace to delete from")
+	filter := fs.String("filter", "", "delete every vector matching this JSON metadata filter")
+	all := fs.Bool("all", false, "delete the entire namespace")
+	yes := fs.Bool("yes", false, "confirm a -filter or -all delete")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	modes := 0
+	if fs.NArg() > 0 {
+		modes++
+	}
+	if *filter != "" {
+		modes++
+	}
+	if *all {
+		modes++
+	}
+	if modes != 1 {
+		return errors.New("specify exactly one of: IDs, -filter or -all")
+	}
+
+	switch {
+	case *all:
+		if !*yes {
+			return fmt.Errorf("refusing to delete namespace %q without -yes", *namespace)
+		}
+		return client.DeleteAllRecordsInNamespace(ctx, *namespace)
+
+	case *filter != "":
+		f, err := parseFilter(*filter)
+		if err != nil {
+			return err
+		}
+		if !*yes {
+			return fmt.Errorf("refusing to delete by filter in namespace %q without -yes", *namespace)
+		}
+		return client.DeleteVectorsByMetadata(ctx, *namespace, f)
+
+	default:
+		return client.DeleteVectorsByID(ctx, fs.Args(), *namespace)
+	}
+}
+
+func cmdStats(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags()
+	filter := fs.String("filter", "", "only count vectors matching this JSON metadata filter")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+	f, err := parseFilter(*filter)
+	if err != nil {
+		return err
+	}
+
+	stats, err := client.DescribeIndexStats(ctx, f)
+	if err != nil {
+		return err
+	}
+
+	return c.print(stats, func(w io.Writer) {
+		fmt.Fprintf(w, "dimension\t%d\n", stats.Dimension)
+		if stats.Metric != "" {
+			fmt.Fprintf(w, "metric\t%s\n", stats.Metric)
+		}
+		fmt.Fprintf(w, "total vectors\t%d\n", stats.TotalVectorCount)
+		fmt.Fprintf(w, "fullness\t%.4f\n", stats.IndexFullness)
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "NAMESPACE\tVECTORS")
+
+		names := make([]string, 0, len(stats.Namespaces))
+		for name := range stats.Namespaces {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(w, "%s\t%d\n", name, stats.Namespaces[name].VectorCount)
+		}
+	})
+}
+
+func cmdExport(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags()
+	namespace := fs.String("namespace", "", "namespace to export")
+	out := fs.String("out", "-", "output file, or - for stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	var count int
+	if *out == "-" {
+		count, err = exportVectors(ctx, client, *namespace, c.stdout)
+	} else {
+		f, ferr := os.Create(*out)
+		if ferr != nil {
+			return ferr
+		}
+		count, err = exportVectors(ctx, client, *namespace, f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.stderr, "exported %d vectors from namespace %q\n", count, *namespace)
+	return nil
+}
+
+// exportVectors writes every vector in namespace to w as JSON lines and
+// returns the number written.
+func exportVectors(ctx context.Context, client *pinecone.Client, namespace string, w io.Writer) (int, error) {
+	enc := json.NewEncoder(w)
+	count := 0
+	token := ""
+	for {
+		ids, next, err := client.ListVectorIDs(ctx, namespace, "", exportBatchSize, token)
+		if err != nil {
+			return count, err
+		}
+
+		if len(ids) > 0 {
+			resp, err := client.FetchVectors(ctx, ids, namespace)
+			if err != nil {
+				return count, err
+			}
+			for _, id := range ids {
+				v, ok := resp.Vectors[id]
+				if !ok {
+					continue
+				}
+				if err := enc.Encode(v); err != nil {
+					return count, err
+				}
+				count++
+			}
+		}
+
+		if next == "" {
+			return count, nil
+		}
+		token = next
+	}
+}
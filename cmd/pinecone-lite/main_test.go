@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("missing_credentials", func(t *testing.T) {
+		t.Setenv("PINECONE_INDEX_URL", "")
+		t.Setenv("PINECONE_API_KEY", "")
+
+		var stdout, stderr bytes.Buffer
+		code := run(context.Background(), []string{"stats"}, &stdout, &stderr)
+		if code != 1 || !strings.Contains(stderr.String(), "missing index URL") {
+			t.Fatalf("expected missing URL error, got %d: %s", code, stderr.String())
+		}
+	})
+
+	t.Run("delete_all_requires_yes", func(t *testing.T) {
+		var called bool
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer s.Close()
+
+		var stdout, stderr bytes.Buffer
+		args := []string{"delete", "-url", s.URL, "-api-key", "k", "-namespace", "ns", "-all"}
+		if code := run(context.Background(), args, &stdout, &stderr); code != 1 {
+			t.Fatalf("expected failure without -yes, got %d", code)
+		}
+		if called {
+			t.Fatal("expected no request without -yes")
+		}
+
+		args = append(args, "-yes")
+		if code := run(context.Background(), args, &stdout, &stderr); code != 0 {
+			t.Fatalf("expected success with -yes, got %d: %s", code, stderr.String())
+		}
+		if !called {
+			t.Fatal("expected delete request with -yes")
+		}
+	})
+
+	t.Run("stats_table_output", func(t *testing.T) {
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"namespaces":{"b":{"vectorCount":2},"a":{"vectorCount":1}},"dimension":3,"totalVectorCount":3}`))
+		}))
+		defer s.Close()
+
+		t.Setenv("PINECONE_INDEX_URL", s.URL)
+		t.Setenv("PINECONE_API_KEY", "k")
+
+		var stdout, stderr bytes.Buffer
+		if code := run(context.Background(), []string{"stats"}, &stdout, &stderr); code != 0 {
+			t.Fatalf("unexpected exit code %d: %s", code, stderr.String())
+		}
+		out := stdout.String()
+		if !strings.Contains(out, "dimension") || strings.Index(out, "a ") > strings.Index(out, "b ") {
+			t.Errorf("unexpected output:\n%s", out)
+		}
+	})
+
+	t.Run("export_to_file", func(t *testing.T) {
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/vectors/list":
+				if r.URL.Query().Get("namespace") != "ns" {
+					t.Errorf("expected the namespace in the query string, got %s", r.URL)
+				}
+				w.Write([]byte(`{"vectors":[{"id":"a"},{"id":"b"}],"pagination":{"next":""}}`))
+			case "/vectors/fetch":
+				w.Write([]byte(`{"vectors":{"a":{"id":"a","values":[1]},"b":{"id":"b","values":[2]}}}`))
+			}
+		}))
+		defer s.Close()
+
+		out := filepath.Join(t.TempDir(), "export.jsonl")
+		var stdout, stderr bytes.Buffer
+		args := []string{"export", "-url", s.URL, "-api-key", "k", "-namespace", "ns", "-out", out}
+		if code := run(context.Background(), args, &stdout, &stderr); code != 0 {
+			t.Fatalf("unexpected exit code %d: %s", code, stderr.String())
+		}
+		data, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 2 {
+			t.Errorf("expected 2 exported vectors, got %q", data)
+		}
+
+		args[len(args)-1] = filepath.Join(t.TempDir(), "missing", "export.jsonl")
+		if code := run(context.Background(), args, &stdout, &stderr); code != 1 {
+			t.Errorf("expected failure for an unwritable output, got %d", code)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package pinecone
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"sync"
+)
+
+// gzipWriterPool holds gzip writers reused across compressed requests.
+var gzipWriterPool = sync.Pool{
+	New: func() any {
+		return gzip.NewWriter(nil)
+	},
+}
+
+// newGzipBody compresses data into a pooled buffer.
+func newGzipBody(data []byte) (*pooledBody, error) {
+	buf := bufferPool.Get().(*[]byte)
+	w := bytes.NewBuffer((*buf)[:0])
+
+	zw := gzipWriterPool.Get().(*gzip.Writer)
+	zw.Reset(w)
+	_, err := zw.Write(data)
+	if err == nil {
+		err = zw.Close()
+	}
+	gzipWriterPool.Put(zw)
+	if err != nil {
+		bufferPool.Put(buf)
+		return nil, err
+	}
+
+	*buf = w.Bytes()
+	return newPooledBuffer(buf), nil
+}
+
+// gzipBody decompresses a gzip-encoded response body. The gzip header is read
+// lazily so that empty bodies, such as those of 204 responses, are not an error.
+type gzipBody struct {
+	body io.ReadCloser
+	zr   *gzip.Reader
+	err  error
+}
+
+// Read reads decompressed bytes from the response body.
+func (g *gzipBody) Read(p []byte) (int, error) {
+	if g.err != nil {
+		return 0, g.err
+	}
+	if g.zr == nil {
+		g.zr, g.err = gzip.NewReader(g.body)
+		if g.err != nil {
+			return 0, g.err
+		}
+	}
+	return g.zr.Read(p)
+}
+
+// Close closes the underlying response body.
+func (g *gzipBody) Close() error {
+	return g.body.Close()
+}
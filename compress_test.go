@@ -0,0 +1,103 @@
+package pinecone
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGzipCompression(t *testing.T) {
+	t.Run("request_compressed_above_threshold", func(t *testing.T) {
+		var encodings []string
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			encodings = append(encodings, r.Header.Get("Content-Encoding"))
+
+			var body io.Reader = r.Body
+			if r.Header.Get("Content-Encoding") == "gzip" {
+				zr, err := gzip.NewReader(r.Body)
+				if err != nil {
+					t.Fatalf("invalid gzip body: %v", err)
+				}
+				body = zr
+			}
+			var payload UpsertRequest
+			if err := json.NewDecoder(body).Decode(&payload); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			json.NewEncoder(w).Encode(UpsertResponse{UpsertedCount: uint32(len(payload.Vectors))})
+		}))
+		defer s.Close()
+
+		client := NewClient(s.URL, "key")
+		client.HTTPClient = s.Client()
+		client.GzipThreshold = 200
+
+		small := []*Vector{{ID: "a", Values: []float32{0.1}}}
+		large := make([]*Vector, 20)
+		for i := range large {
+			large[i] = &Vector{ID: "v", Values: []float32{0.1, 0.2, 0.3}}
+		}
+
+		for _, vectors := range [][]*Vector{small, large} {
+			n, err := client.UpsertVectors(context.Background(), vectors, "ns")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if int(n) != len(vectors) {
+				t.Errorf("expected %d upserted, got %d", len(vectors), n)
+			}
+		}
+		if encodings[0] != "" || encodings[1] != "gzip" {
+			t.Errorf("expected only the large request compressed, got %q", encodings)
+		}
+	})
+
+	t.Run("response_decompressed", func(t *testing.T) {
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Accept-Encoding") != "gzip" {
+				t.Errorf("expected Accept-Encoding gzip, got %q", r.Header.Get("Accept-Encoding"))
+			}
+			w.Header().Set("Content-Encoding", "gzip")
+			zw := gzip.NewWriter(w)
+			zw.Write([]byte(`{"matches":[{"id":"rec1","score":0.9,"values":[0.1,0.2]}],"namespace":"ns"}`))
+			zw.Close()
+		}))
+		defer s.Close()
+
+		client := NewClient(s.URL, "key")
+		client.HTTPClient = s.Client()
+		client.AcceptGzip = true
+
+		resp, err := client.QueryByVector(context.Background(), &QueryByVectorRequest{
+			Vector:        []float32{0.1, 0.2},
+			TopK:          1,
+			IncludeValues: true,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(resp.Matches) != 1 || len(resp.Matches[0].Values) != 2 {
+			t.Errorf("unexpected response: %+v", resp)
+		}
+	})
+
+	t.Run("empty_compressed_response", func(t *testing.T) {
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Encoding", "gzip")
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer s.Close()
+
+		client := NewClient(s.URL, "key")
+		client.HTTPClient = s.Client()
+		client.AcceptGzip = true
+
+		if err := client.DeleteAllRecordsInNamespace(context.Background(), "ns"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
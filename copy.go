@@ -0,0 +1,306 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// CopyOptions configures CopyNamespace.
+type CopyOptions struct {
+	// BatchSize is the number of IDs listed, fetched and upserted at a time.
+	// Defaults to 100.
+	BatchSize int
+
+	// Concurrency is the number of batches copied concurrently. Defaults to
+	// the destination client's MaxConcurrency.
+	Concurrency int
+
+	// CheckpointPath, if set, records progress in this file so an interrupted
+	// copy resumes where it stopped. The file is removed once the copy
+	// completes.
+	CheckpointPath string
+
+	// Transform, if set, is applied to each vector before it is upserted, for
+	// example to rewrite metadata. Changing the ID makes a move fail
+	// verification, leaving the source in place.
+	Transform func(v *Vector) error
+
+	// Move deletes the source namespace once every source ID has been
+	// verified to exist in the destination.
+	Move bool
+}
+
+// CopyReport describes the outcome of CopyNamespace.
+type CopyReport struct {
+	// Copied counts the vectors upserted into the destination, including those
+	// copied before resuming from a checkpoint.
+	Copied int
+
+	// Moved reports whether the source namespace was deleted.
+	Moved bool
+}
+
+// checkpoint records the progress of a namespace migration such as
+// CopyNamespace. Token is the list pagination token of the first batch not yet
+// known to be processed.
+type checkpoint struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Token       string `json:"token"`
+	Copied      int    `json:"copied"`
+	Skipped     int    `json:"skipped,omitempty"`
+	Done        bool   `json:"done"`
+}
+
+// CopyNamespace copies every vector in srcNS to dstNS, which may be in another
+// index. It streams the source in batches, listing IDs, fetching their vectors
+// and upserting them, with several batches in flight at once.
+//
+// Copying is idempotent, so a failed copy can simply be retried; with a
+// checkpoint it resumes from the last batch known to be complete.
+func CopyNamespace(ctx context.Context, srcClient *Client, srcNS string, dstClient *Client, dstNS string, opts *CopyOptions) (*CopyReport, error) {
+	if opts == nil {
+		opts = &CopyOptions{}
+	}
+	if srcClient.IndexURL == dstClient.IndexURL && srcNS == dstNS {
+		return nil, errors.New("pinecone: copy source and destination are the same namespace")
+	}
+
+	cp, err := resumeCheckpoint(opts.CheckpointPath, srcClient.IndexURL+"/"+srcNS, dstClient.IndexURL+"/"+dstNS)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CopyReport{}
+	if !cp.Done {
+		if err := copyBatches(ctx, srcClient, srcNS, dstClient, dstNS, opts, cp); err != nil {
+			report.Copied = cp.Copied
+			return report, err
+		}
+	}
+	report.Copied = cp.Copied
+
+	if opts.Move {
+		if err := verifyCopied(ctx, srcClient, srcNS, dstClient, dstNS); err != nil {
+			return report, err
+		}
+		if err := srcClient.DeleteAllRecordsInNamespace(ctx, srcNS); err != nil {
+			return report, err
+		}
+		report.Moved = true
+	}
+
+	if err := removeCheckpoint(opts.CheckpointPath); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// copyBatches copies the source from cp.Token onwards, advancing cp as
+// batches complete in order.
+func copyBatches(ctx context.Context, srcClient *Client, srcNS string, dstClient *Client, dstNS string, opts *CopyOptions, cp *checkpoint) error {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 || batchSize > maxFetchIDs {
+		batchSize = maxFetchIDs
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = dstClient.maxConcurrency()
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	// Batches finish out of order, so the checkpoint only advances past a
+	// batch once every earlier batch has finished too.
+	var mu sync.Mutex
+	type batchState struct {
+		next   string
+		copied int
+		done   bool
+	}
+	var batches []*batchState
+	committed := 0
+	finish := func(b *batchState, copied int) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		b.copied, b.done = copied, true
+		advanced := false
+		for committed < len(batches) && batches[committed].done {
+			cp.Token = batches[committed].next
+			cp.Copied += batches[committed].copied
+			cp.Done = cp.Token == ""
+			committed++
+			advanced = true
+		}
+		if advanced && opts.CheckpointPath != "" {
+			return saveCheckpoint(opts.CheckpointPath, cp)
+		}
+		return nil
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	token := cp.Token
+	for {
+		ids, next, err := srcClient.ListVectorIDs(ctx, srcNS, "", batchSize, token)
+		if err != nil {
+			cancel(err)
+			break
+		}
+
+		b := &batchState{next: next}
+		mu.Lock()
+		batches = append(batches, b)
+		mu.Unlock()
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			n, err := copyBatch(ctx, srcClient, srcNS, dstClient, dstNS, ids, opts.Transform)
+			if err == nil {
+				err = finish(b, n)
+			}
+			if err != nil {
+				cancel(err)
+			}
+		}()
+
+		if next == "" {
+			break
+		}
+		token = next
+	}
+	wg.Wait()
+
+	return context.Cause(ctx)
+}
+
+// copyBatch fetches ids from the source and upserts them into the
+// destination, returning the number of vectors copied.
+func copyBatch(ctx context.Context, srcClient *Client, srcNS string, dstClient *Client, dstNS string, ids []string, transform func(*Vector) error) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	resp, err := srcClient.FetchVectors(ctx, ids, srcNS)
+	if err != nil {
+		return 0, err
+	}
+
+	vectors := make([]*Vector, 0, len(resp.Vectors))
+	for _, id := range ids {
+		v, ok := resp.Vectors[id]
+		if !ok {
+			continue
+		}
+		if transform != nil {
+			if err := transform(v); err != nil {
+				return 0, fmt.Errorf("pinecone: transforming vector %q: %w", id, err)
+			}
+		}
+		vectors = append(vectors, v)
+	}
+	if len(vectors) == 0 {
+		return 0, nil
+	}
+
+	if _, err := dstClient.UpsertVectors(ctx, vectors, dstNS); err != nil {
+		return 0, err
+	}
+	return len(vectors), nil
+}
+
+// verifyCopied checks that every source ID exists in the destination.
+func verifyCopied(ctx context.Context, srcClient *Client, srcNS string, dstClient *Client, dstNS string) error {
+	ids, err := srcClient.listAllIDs(ctx, srcNS, "")
+	if err != nil {
+		return err
+	}
+
+	missing := 0
+	for start := 0; start < len(ids); start += maxFetchIDs {
+		batch := ids[start:min(start+maxFetchIDs, len(ids))]
+		resp, err := dstClient.FetchVectors(ctx, batch, dstNS)
+		if err != nil {
+			return err
+		}
+		missing += len(batch) - len(resp.Vectors)
+	}
+	if missing > 0 {
+		return fmt.Errorf("pinecone: move verification failed: %d of %d vectors missing from the destination", missing, len(ids))
+	}
+	return nil
+}
+
+// resumeCheckpoint loads the checkpoint at path for migrating source to
+// destination, returning a fresh checkpoint if path is empty or does not exist.
+func resumeCheckpoint(path, source, destination string) (*checkpoint, error) {
+	cp := &checkpoint{Source: source, Destination: destination}
+	if path == "" {
+		return cp, nil
+	}
+
+	saved, err := loadCheckpoint(path)
+	if err != nil || saved == nil {
+		return cp, err
+	}
+	if saved.Source != source || saved.Destination != destination {
+		return nil, fmt.Errorf("pinecone: checkpoint %s is for %s to %s", path, saved.Source, saved.Destination)
+	}
+	return saved, nil
+}
+
+// removeCheckpoint deletes the checkpoint at path, if any, once a migration
+// completes.
+func removeCheckpoint(path string) error {
+	if path == "" {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// loadCheckpoint reads the checkpoint at path, returning nil if the file
+// does not exist.
+func loadCheckpoint(path string) (*checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+// saveCheckpoint atomically writes cp to path.
+func saveCheckpoint(path string, cp *checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
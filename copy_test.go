@@ -0,0 +1,127 @@
+package pinecone
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestCopyNamespace(t *testing.T) {
+	ctx := context.Background()
+	seed := func(m *memoryIndex) {
+		for _, id := range []string{"a", "b", "c", "d", "e"} {
+			m.put("src", &Vector{ID: id, Values: []float32{1, 2}, Metadata: map[string]any{"v": 1}})
+		}
+	}
+
+	t.Run("across_indexes_with_transform", func(t *testing.T) {
+		srcIndex, srcClient := newMemoryIndex(t)
+		dstIndex, dstClient := newMemoryIndex(t)
+		seed(srcIndex)
+
+		report, err := CopyNamespace(ctx, srcClient, "src", dstClient, "dst", &CopyOptions{
+			BatchSize: 2,
+			Transform: func(v *Vector) error {
+				v.Metadata["v"] = 2
+				return nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("copy failed: %v", err)
+		}
+		if report.Copied != 5 || report.Moved {
+			t.Errorf("unexpected report: %+v", report)
+		}
+		if got := dstIndex.ids("dst"); len(got) != 5 {
+			t.Errorf("expected 5 vectors in the destination, got %v", got)
+		}
+		if v := dstIndex.namespaces["dst"]["c"].Metadata["v"]; v != 2.0 {
+			t.Errorf("expected transformed metadata, got %v", v)
+		}
+		if got := srcIndex.ids("src"); len(got) != 5 {
+			t.Errorf("copy should leave the source in place, got %v", got)
+		}
+	})
+
+	t.Run("resumes_from_checkpoint", func(t *testing.T) {
+		srcIndex, srcClient := newMemoryIndex(t)
+		dstIndex, dstClient := newMemoryIndex(t)
+		seed(srcIndex)
+		checkpoint := filepath.Join(t.TempDir(), "copy.json")
+
+		boom := errors.New("boom")
+		_, err := CopyNamespace(ctx, srcClient, "src", dstClient, "dst", &CopyOptions{
+			BatchSize:      2,
+			Concurrency:    1,
+			CheckpointPath: checkpoint,
+			Transform: func(v *Vector) error {
+				if v.ID == "c" {
+					return boom
+				}
+				return nil
+			},
+		})
+		if !errors.Is(err, boom) {
+			t.Fatalf("expected the transform error, got %v", err)
+		}
+		if _, err := os.Stat(checkpoint); err != nil {
+			t.Fatalf("expected a checkpoint after failure: %v", err)
+		}
+
+		report, err := CopyNamespace(ctx, srcClient, "src", dstClient, "dst", &CopyOptions{
+			BatchSize:      2,
+			CheckpointPath: checkpoint,
+		})
+		if err != nil {
+			t.Fatalf("resumed copy failed: %v", err)
+		}
+		if report.Copied != 5 || dstIndex.upserts != 5 {
+			t.Errorf("expected the first batch not to be copied again, got %+v after %d upserts", report, dstIndex.upserts)
+		}
+		if _, err := os.Stat(checkpoint); !os.IsNotExist(err) {
+			t.Errorf("expected the checkpoint to be removed, got %v", err)
+		}
+	})
+
+	t.Run("move", func(t *testing.T) {
+		m, c := newMemoryIndex(t)
+		seed(m)
+
+		report, err := CopyNamespace(ctx, c, "src", c, "renamed", &CopyOptions{Move: true})
+		if err != nil {
+			t.Fatalf("move failed: %v", err)
+		}
+		if !report.Moved || len(m.ids("src")) != 0 || !slices.Equal(m.ids("renamed"), []string{"a", "b", "c", "d", "e"}) {
+			t.Errorf("unexpected result: %+v src=%v dst=%v", report, m.ids("src"), m.ids("renamed"))
+		}
+	})
+
+	t.Run("move_keeps_source_when_verification_fails", func(t *testing.T) {
+		m, c := newMemoryIndex(t)
+		seed(m)
+
+		_, err := CopyNamespace(ctx, c, "src", c, "renamed", &CopyOptions{
+			Move: true,
+			Transform: func(v *Vector) error {
+				v.ID += "-new"
+				return nil
+			},
+		})
+		if err == nil {
+			t.Fatal("expected verification to fail")
+		}
+		if len(m.ids("src")) != 5 {
+			t.Errorf("expected the source to be kept, got %v", m.ids("src"))
+		}
+	})
+
+	t.Run("same_namespace", func(t *testing.T) {
+		_, c := newMemoryIndex(t)
+		if _, err := CopyNamespace(ctx, c, "ns", c, "ns", nil); err == nil {
+			t.Error("expected an error copying a namespace onto itself")
+		}
+	})
+}
@@ -13,6 +13,7 @@ func (c *Client) DeleteVectorsByID(ctx context.Context, ids []string, namespace
 		"namespace": namespace,
 	}
 
+	defer c.invalidate(namespace)
 	resp, err := c.do(ctx, http.MethodPost, "/vectors/delete", payload)
 	if err != nil {
 		return err
@@ -29,6 +30,7 @@ func (c *Client) DeleteVectorsByID(ctx context.Context, ids []string, namespace
 // This is a destructive operation: the namespace and all its associated data
 // will be permanently deleted from the Pinecone index.
 func (c *Client) DeleteAllRecordsInNamespace(ctx context.Context, namespace string) error {
+	defer c.invalidate(namespace)
 	resp, err := c.do(ctx, http.MethodDelete, "/namespaces/"+namespace, nil)
 	if err != nil {
 		return err
@@ -59,6 +61,7 @@ func (c *Client) DeleteVectorsByMetadata(ctx context.Context, namespace string,
 		"filter":    filter,
 	}
 
+	defer c.invalidate(namespace)
 	resp, err := c.do(ctx, http.MethodPost, "/vectors/delete", body)
 	if err != nil {
 		return err
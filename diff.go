@@ -0,0 +1,216 @@
+package pinecone
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+)
+
+// NamespaceRef identifies a namespace in an index.
+type NamespaceRef struct {
+	Client    *Client
+	Namespace string
+}
+
+// DiffOptions configures DiffNamespaces.
+type DiffOptions struct {
+	// Tolerance is the largest absolute difference allowed between
+	// corresponding vector components before the vectors are reported as
+	// changed. Zero requires exact equality.
+	Tolerance float64
+
+	// IgnoreFields lists metadata fields left out of the comparison, such as
+	// DefaultHashField.
+	IgnoreFields []string
+
+	// BatchSize is the number of vectors fetched per request. Defaults to 100.
+	BatchSize int
+}
+
+// NamespaceDiff describes how two namespaces differ. Each list is sorted.
+type NamespaceDiff struct {
+	// MissingInDst holds IDs present in the source but not the destination.
+	MissingInDst []string
+
+	// MissingInSrc holds IDs present in the destination but not the source.
+	MissingInSrc []string
+
+	// Changed holds IDs present in both whose values or metadata differ.
+	Changed []string
+}
+
+// Equal reports whether the namespaces hold the same data.
+func (d *NamespaceDiff) Equal() bool {
+	return len(d.MissingInDst) == 0 && len(d.MissingInSrc) == 0 && len(d.Changed) == 0
+}
+
+// DiffNamespaces compares the vectors of two namespaces, which may live in
+// different indexes. It lists the IDs on both sides, then fetches the IDs they
+// share in batches and compares their values and metadata.
+func DiffNamespaces(ctx context.Context, src, dst NamespaceRef, opts *DiffOptions) (*NamespaceDiff, error) {
+	if opts == nil {
+		opts = &DiffOptions{}
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 || batchSize > maxFetchIDs {
+		batchSize = maxFetchIDs
+	}
+
+	srcIDs, err := src.Client.listAllIDs(ctx, src.Namespace, "")
+	if err != nil {
+		return nil, err
+	}
+	dstIDs, err := dst.Client.listAllIDs(ctx, dst.Namespace, "")
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(srcIDs)
+	slices.Sort(dstIDs)
+
+	diff := &NamespaceDiff{}
+	var shared []string
+	i, j := 0, 0
+	for i < len(srcIDs) || j < len(dstIDs) {
+		switch {
+		case j == len(dstIDs) || (i < len(srcIDs) && srcIDs[i] < dstIDs[j]):
+			diff.MissingInDst = append(diff.MissingInDst, srcIDs[i])
+			i++
+		case i == len(srcIDs) || dstIDs[j] < srcIDs[i]:
+			diff.MissingInSrc = append(diff.MissingInSrc, dstIDs[j])
+			j++
+		default:
+			shared = append(shared, srcIDs[i])
+			i++
+			j++
+		}
+	}
+
+	for start := 0; start < len(shared); start += batchSize {
+		batch := shared[start:min(start+batchSize, len(shared))]
+		a, err := src.Client.FetchVectors(ctx, batch, src.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		b, err := dst.Client.FetchVectors(ctx, batch, dst.Namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range batch {
+			same, err := sameVector(a.Vectors[id], b.Vectors[id], opts)
+			if err != nil {
+				return nil, err
+			}
+			if !same {
+				diff.Changed = append(diff.Changed, id)
+			}
+		}
+	}
+	return diff, nil
+}
+
+// Reconcile applies diff to dst, copying the vectors missing from it or
+// changed in src. When deleteExtra is set, vectors missing from src are
+// deleted from dst.
+func Reconcile(ctx context.Context, src, dst NamespaceRef, diff *NamespaceDiff, deleteExtra bool) error {
+	ids := append(slices.Clone(diff.MissingInDst), diff.Changed...)
+	for start := 0; start < len(ids); start += maxFetchIDs {
+		batch := ids[start:min(start+maxFetchIDs, len(ids))]
+		resp, err := src.Client.FetchVectors(ctx, batch, src.Namespace)
+		if err != nil {
+			return err
+		}
+
+		vectors := make([]*Vector, 0, len(resp.Vectors))
+		for _, id := range batch {
+			if v, ok := resp.Vectors[id]; ok {
+				vectors = append(vectors, v)
+			}
+		}
+		if len(vectors) == 0 {
+			continue
+		}
+		if _, err := dst.Client.UpsertVectors(ctx, vectors, dst.Namespace); err != nil {
+			return err
+		}
+	}
+
+	if !deleteExtra {
+		return nil
+	}
+	for start := 0; start < len(diff.MissingInSrc); start += maxDeleteIDs {
+		batch := diff.MissingInSrc[start:min(start+maxDeleteIDs, len(diff.MissingInSrc))]
+		if err := dst.Client.DeleteVectorsByID(ctx, batch, dst.Namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sameVector reports whether a and b hold the same values, within
+// opts.Tolerance, and the same metadata. A vector deleted between listing and
+// fetching compares unequal to one that still exists.
+func sameVector(a, b *Vector, opts *DiffOptions) (bool, error) {
+	if a == nil || b == nil {
+		return a == b, nil
+	}
+	if !closeValues(a.Values, b.Values, opts.Tolerance) {
+		return false, nil
+	}
+
+	sa, sb := a.SparseValues, b.SparseValues
+	if (sa == nil) != (sb == nil) {
+		return false, nil
+	}
+	if sa != nil && (!slices.Equal(sa.Indices, sb.Indices) || !closeValues(sa.Values, sb.Values, opts.Tolerance)) {
+		return false, nil
+	}
+
+	ma, err := canonicalMetadata(a.Metadata, opts.IgnoreFields)
+	if err != nil {
+		return false, err
+	}
+	mb, err := canonicalMetadata(b.Metadata, opts.IgnoreFields)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(ma, mb), nil
+}
+
+// closeValues reports whether a and b have the same length and no components
+// further apart than tolerance.
+func closeValues(a, b []float32, tolerance float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		d := float64(a[i]) - float64(b[i])
+		if d > tolerance || -d > tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// canonicalMetadata encodes md without the ignored fields. encoding/json sorts
+// map keys and writes 1 and 1.0 alike, so equal metadata encodes identically
+// however it was decoded.
+func canonicalMetadata(md map[string]any, ignore []string) ([]byte, error) {
+	if len(md) == 0 {
+		return nil, nil
+	}
+	if len(ignore) > 0 {
+		filtered := make(map[string]any, len(md))
+		for k, v := range md {
+			if !slices.Contains(ignore, k) {
+				filtered[k] = v
+			}
+		}
+		md = filtered
+	}
+	if len(md) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(md)
+}
@@ -0,0 +1,74 @@
+package pinecone
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestDiffNamespaces(t *testing.T) {
+	ctx := context.Background()
+	srcIndex, srcClient := newMemoryIndex(t)
+	dstIndex, dstClient := newMemoryIndex(t)
+
+	srcIndex.put("ns",
+		&Vector{ID: "a", Values: []float32{1, 2}, Metadata: map[string]any{"k": "v"}},
+		&Vector{ID: "b", Values: []float32{3, 4}},
+		&Vector{ID: "c", Values: []float32{5, 6}, Metadata: map[string]any{"n": 1}},
+		&Vector{ID: "d", Values: []float32{7, 8}},
+		&Vector{ID: "e", Values: []float32{9, 10}},
+	)
+	dstIndex.put("copy",
+		&Vector{ID: "a", Values: []float32{1, 2.0000001}, Metadata: map[string]any{"k": "v", DefaultHashField: "x"}},
+		&Vector{ID: "b", Values: []float32{3, 5}},
+		&Vector{ID: "c", Values: []float32{5, 6}, Metadata: map[string]any{"n": 2}},
+		&Vector{ID: "z", Values: []float32{0, 0}},
+	)
+
+	src := NamespaceRef{Client: srcClient, Namespace: "ns"}
+	dst := NamespaceRef{Client: dstClient, Namespace: "copy"}
+	opts := &DiffOptions{Tolerance: 1e-5, IgnoreFields: []string{DefaultHashField}, BatchSize: 2}
+
+	diff, err := DiffNamespaces(ctx, src, dst, opts)
+	if err != nil {
+		t.Fatalf("diff failed: %v", err)
+	}
+	if !slices.Equal(diff.MissingInDst, []string{"d", "e"}) {
+		t.Errorf("unexpected MissingInDst: %v", diff.MissingInDst)
+	}
+	if !slices.Equal(diff.MissingInSrc, []string{"z"}) {
+		t.Errorf("unexpected MissingInSrc: %v", diff.MissingInSrc)
+	}
+	if !slices.Equal(diff.Changed, []string{"b", "c"}) {
+		t.Errorf("unexpected Changed: %v", diff.Changed)
+	}
+	if diff.Equal() {
+		t.Error("expected the namespaces to differ")
+	}
+
+	if err := Reconcile(ctx, src, dst, diff, true); err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+	diff, err = DiffNamespaces(ctx, src, dst, opts)
+	if err != nil {
+		t.Fatalf("second diff failed: %v", err)
+	}
+	if !diff.Equal() {
+		t.Errorf("expected the namespaces to match after reconcile, got %+v", diff)
+	}
+}
+
+func TestSameVector(t *testing.T) {
+	a := &Vector{ID: "a", SparseValues: &SparseValues{Indices: []uint32{1, 2}, Values: []float32{0.5, 0.25}}}
+	b := &Vector{ID: "a", SparseValues: &SparseValues{Indices: []uint32{1, 3}, Values: []float32{0.5, 0.25}}}
+
+	if same, _ := sameVector(a, a, &DiffOptions{}); !same {
+		t.Error("expected a vector to equal itself")
+	}
+	if same, _ := sameVector(a, b, &DiffOptions{}); same {
+		t.Error("expected different sparse indices to differ")
+	}
+	if same, _ := sameVector(a, nil, &DiffOptions{}); same {
+		t.Error("expected a missing vector to differ")
+	}
+}
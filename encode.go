@@ -0,0 +1,308 @@
+package pinecone
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+// maxPooledBuffer is the largest buffer returned to bufferPool. Larger buffers
+// are left to the garbage collector so one oversized request does not pin
+// memory for the life of the process.
+const maxPooledBuffer = 64 << 20
+
+// bufferPool holds request body buffers reused across requests.
+var bufferPool = sync.Pool{
+	New: func() any {
+		b := make([]byte, 0, 64*1024)
+		return &b
+	},
+}
+
+// jsonAppender is implemented by request payloads that encode themselves
+// directly into a byte slice, avoiding reflection and intermediate buffers
+// for large requests.
+type jsonAppender interface {
+	appendJSON(dst []byte) ([]byte, error)
+}
+
+// pooledBody is a request body backed by a pooled buffer. Bodies created by
+// clone share the buffer, which is returned to the pool once every one of
+// them has been closed. Keeping a clone open lets the buffer be replayed for
+// redirects and retries after the transport has closed the original.
+type pooledBody struct {
+	mu     sync.Mutex
+	shared *pooledBuffer
+	r      *bytes.Reader
+	size   int
+}
+
+// pooledBuffer is a pooled buffer shared by reference-counted pooledBodies.
+type pooledBuffer struct {
+	mu   sync.Mutex
+	buf  *[]byte
+	refs int
+}
+
+// newPooledBuffer wraps buf, taking ownership of it, in a pooledBody.
+func newPooledBuffer(buf *[]byte) *pooledBody {
+	shared := &pooledBuffer{buf: buf, refs: 1}
+	return &pooledBody{shared: shared, r: bytes.NewReader(*buf), size: len(*buf)}
+}
+
+// newPooledBody encodes a into a pooled buffer.
+func newPooledBody(a jsonAppender) (*pooledBody, error) {
+	buf := bufferPool.Get().(*[]byte)
+	b, err := a.appendJSON((*buf)[:0])
+	if err != nil {
+		bufferPool.Put(buf)
+		return nil, err
+	}
+	*buf = b
+	return newPooledBuffer(buf), nil
+}
+
+// clone returns a new body reading the same buffer from the start. It fails
+// once every body sharing the buffer has been closed.
+func (p *pooledBody) clone() (*pooledBody, error) {
+	s := p.shared
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.buf == nil {
+		return nil, io.ErrClosedPipe
+	}
+	s.refs++
+	return &pooledBody{shared: s, r: bytes.NewReader(*s.buf), size: p.size}, nil
+}
+
+// bytes returns the encoded body. It must not be called after Close.
+func (p *pooledBody) bytes() []byte {
+	return *p.shared.buf
+}
+
+// jsonValue adapts an arbitrary value to jsonAppender using json.Marshal.
+type jsonValue struct {
+	v any
+}
+
+// appendJSON appends the json.Marshal encoding of the wrapped value to dst.
+func (j jsonValue) appendJSON(dst []byte) ([]byte, error) {
+	b, err := json.Marshal(j.v)
+	if err != nil {
+		return nil, err
+	}
+	return append(dst, b...), nil
+}
+
+// Len returns the encoded size of the body. It remains valid after Close.
+func (p *pooledBody) Len() int {
+	return p.size
+}
+
+// Read reads from the encoded body.
+func (p *pooledBody) Read(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.r == nil {
+		return 0, io.ErrClosedPipe
+	}
+	return p.r.Read(b)
+}
+
+// Close releases this body's reference to the buffer, returning the buffer to
+// the pool once no body uses it. It is safe to call more than once.
+func (p *pooledBody) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.r == nil {
+		return nil
+	}
+	p.r = nil
+
+	s := p.shared
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.refs--; s.refs > 0 {
+		return nil
+	}
+	if cap(*s.buf) <= maxPooledBuffer {
+		bufferPool.Put(s.buf)
+	}
+	s.buf = nil
+	return nil
+}
+
+// appendJSON encodes the upsert request into dst. The output is equivalent
+// to json.Marshal(r).
+func (r *UpsertRequest) appendJSON(dst []byte) ([]byte, error) {
+	var err error
+	dst = append(dst, `{"vectors":`...)
+	if r.Vectors == nil {
+		dst = append(dst, "null"...)
+	} else {
+		dst = append(dst, '[')
+		for i, v := range r.Vectors {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			if dst, err = v.appendJSON(dst); err != nil {
+				return nil, err
+			}
+		}
+		dst = append(dst, ']')
+	}
+	if r.Namespace != "" {
+		dst = append(dst, `,"namespace":`...)
+		dst = appendJSONString(dst, r.Namespace)
+	}
+	return append(dst, '}'), nil
+}
+
+// appendJSON encodes the vector into dst. The output is equivalent to
+// json.Marshal(v).
+func (v *Vector) appendJSON(dst []byte) ([]byte, error) {
+	if v == nil {
+		return append(dst, "null"...), nil
+	}
+
+	var err error
+	dst = append(dst, `{"id":`...)
+	dst = appendJSONString(dst, v.ID)
+	if len(v.Values) > 0 {
+		dst = append(dst, `,"values":`...)
+		if dst, err = appendFloat32s(dst, v.Values); err != nil {
+			return nil, err
+		}
+	}
+	if v.SparseValues != nil {
+		dst = append(dst, `,"sparseValues":{"indices":`...)
+		if v.SparseValues.Indices == nil {
+			dst = append(dst, "null"...)
+		} else {
+			dst = append(dst, '[')
+			for i, idx := range v.SparseValues.Indices {
+				if i > 0 {
+					dst = append(dst, ',')
+				}
+				dst = strconv.AppendUint(dst, uint64(idx), 10)
+			}
+			dst = append(dst, ']')
+		}
+		dst = append(dst, `,"values":`...)
+		if dst, err = appendFloat32s(dst, v.SparseValues.Values); err != nil {
+			return nil, err
+		}
+		dst = append(dst, '}')
+	}
+	if len(v.Metadata) > 0 {
+		b, err := json.Marshal(v.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		dst = append(dst, `,"metadata":`...)
+		dst = append(dst, b...)
+	}
+	return append(dst, '}'), nil
+}
+
+// appendFloat32s encodes values as a JSON array.
+func appendFloat32s(dst []byte, values []float32) ([]byte, error) {
+	if values == nil {
+		return append(dst, "null"...), nil
+	}
+	dst = append(dst, '[')
+	for i, x := range values {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		var err error
+		if dst, err = appendFloat32(dst, x); err != nil {
+			return nil, err
+		}
+	}
+	return append(dst, ']'), nil
+}
+
+// errUnsupportedFloat is returned when a value cannot be represented in JSON.
+var errUnsupportedFloat = errors.New("pinecone: vector values must be finite")
+
+// appendFloat32 formats x the way encoding/json formats a float32.
+func appendFloat32(dst []byte, x float32) ([]byte, error) {
+	f := float64(x)
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, errUnsupportedFloat
+	}
+
+	abs := math.Abs(f)
+	format := byte('f')
+	if abs != 0 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+		format = 'e'
+	}
+	dst = strconv.AppendFloat(dst, f, format, -1, 32)
+	if format == 'e' {
+		// Clean up e-09 to e-9, matching encoding/json.
+		n := len(dst)
+		if n >= 4 && dst[n-4] == 'e' && dst[n-3] == '-' && dst[n-2] == '0' {
+			dst[n-2] = dst[n-1]
+			dst = dst[:n-1]
+		}
+	}
+	return dst, nil
+}
+
+// appendJSONString appends s as a quoted JSON string, escaping it the way
+// encoding/json does.
+func appendJSONString(dst []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				dst = append(dst, '\\', c)
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hex[r&0xf])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
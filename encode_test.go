@@ -0,0 +1,190 @@
+package pinecone
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestUpsertRequestAppendJSON(t *testing.T) {
+	t.Run("matches_json_marshal", func(t *testing.T) {
+		req := &UpsertRequest{
+			Vectors: []*Vector{
+				{ID: "plain", Values: []float32{0, 1, -2.5, 0.1, 1e-7, 3.4e21, 123456.79}},
+				{ID: "esc\"aped\\<&>\n\t\x01\u2028", Values: []float32{0.3}, Metadata: map[string]any{"genre": "drama", "year": 2019}},
+				{ID: "bad\xffutf8", Values: []float32{}},
+				{ID: "nil-values"},
+				{ID: "sparse", Values: []float32{0.2}, SparseValues: &SparseValues{Indices: []uint32{3, 4294967295}, Values: []float32{0.5, 1}}},
+				{ID: "sparse-empty", SparseValues: &SparseValues{}},
+			},
+			Namespace: "ns",
+		}
+
+		want, err := json.Marshal(req)
+		if err != nil {
+			t.Fatalf("marshal failed: %v", err)
+		}
+		got, err := req.appendJSON(nil)
+		if err != nil {
+			t.Fatalf("appendJSON failed: %v", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("encoding mismatch:\n got: %s\nwant: %s", got, want)
+		}
+	})
+
+	t.Run("rejects_non_finite", func(t *testing.T) {
+		req := &UpsertRequest{Vectors: []*Vector{{ID: "v", Values: []float32{float32(math.NaN())}}}}
+		if _, err := req.appendJSON(nil); err != errUnsupportedFloat {
+			t.Fatalf("expected errUnsupportedFloat, got %v", err)
+		}
+	})
+
+	t.Run("sent_with_content_length", func(t *testing.T) {
+		var gotLength int64
+		var gotBody []byte
+		client := &Client{
+			IndexURL: "https://example-index.svc.us-east1-gcp.io",
+			APIKey:   "test-key",
+			HTTPClient: &http.Client{
+				Transport: roundTripFunc(func(req *http.Request) *http.Response {
+					gotLength = req.ContentLength
+					gotBody, _ = io.ReadAll(req.Body)
+					req.Body.Close()
+					return &http.Response{
+						StatusCode: 200,
+						Body:       io.NopCloser(bytes.NewReader([]byte(`{"upsertedCount":1}`))),
+						Header:     make(http.Header),
+					}
+				}),
+			},
+		}
+
+		_, err := client.UpsertVectors(context.Background(), []*Vector{{ID: "v", Values: []float32{0.5}}}, "ns")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := `{"vectors":[{"id":"v","values":[0.5]}],"namespace":"ns"}`; string(gotBody) != want {
+			t.Errorf("unexpected body: %s", gotBody)
+		}
+		if gotLength != int64(len(gotBody)) {
+			t.Errorf("expected content length %d, got %d", len(gotBody), gotLength)
+		}
+	})
+}
+
+func TestPooledBody(t *testing.T) {
+	t.Run("read_after_close", func(t *testing.T) {
+		pb, err := newPooledBody(&UpsertRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		pb.Close()
+		pb.Close()
+		if _, err := pb.Read(make([]byte, 8)); err != io.ErrClosedPipe {
+			t.Fatalf("expected ErrClosedPipe, got %v", err)
+		}
+		if pb.Len() != len(`{"vectors":null}`) {
+			t.Errorf("expected Len to survive Close, got %d", pb.Len())
+		}
+		if _, err := pb.clone(); err != io.ErrClosedPipe {
+			t.Errorf("expected clone after Close to fail, got %v", err)
+		}
+	})
+
+	t.Run("clone_outlives_original", func(t *testing.T) {
+		pb, err := newPooledBody(&UpsertRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		clone, err := pb.clone()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		io.ReadAll(pb)
+		pb.Close()
+
+		got, err := io.ReadAll(clone)
+		if err != nil || string(got) != `{"vectors":null}` {
+			t.Errorf("expected the clone to read the whole body, got %q, %v", got, err)
+		}
+		clone.Close()
+	})
+
+	t.Run("replayed_on_redirect", func(t *testing.T) {
+		var bodies []string
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(b))
+			if r.URL.Path == "/vectors/upsert" {
+				http.Redirect(w, r, "/moved", http.StatusTemporaryRedirect)
+				return
+			}
+			w.Write([]byte(`{"upsertedCount":1}`))
+		}))
+		defer s.Close()
+
+		client := NewClient(s.URL, "key")
+		if _, err := client.UpsertVectors(context.Background(), []*Vector{{ID: "v", Values: []float32{1}}}, "ns"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(bodies) != 2 || bodies[0] != bodies[1] || bodies[1] == "" {
+			t.Errorf("expected the body to be replayed to the redirect target, got %q", bodies)
+		}
+	})
+}
+
+// benchmarkUpsertRequest builds a 1000×3072 upsert, the size of a typical
+// large batch of text-embedding-3-large vectors.
+func benchmarkUpsertRequest() *UpsertRequest {
+	rng := rand.New(rand.NewSource(1))
+	vectors := make([]*Vector, 1000)
+	for i := range vectors {
+		values := make([]float32, 3072)
+		for j := range values {
+			values[j] = rng.Float32()*2 - 1
+		}
+		vectors[i] = &Vector{
+			ID:       "vec-" + strconv.Itoa(i),
+			Values:   values,
+			Metadata: map[string]any{"doc_id": strconv.Itoa(i / 10)},
+		}
+	}
+	return &UpsertRequest{Vectors: vectors, Namespace: "bench"}
+}
+
+func BenchmarkUpsertEncode(b *testing.B) {
+	req := benchmarkUpsertRequest()
+	size, _ := json.Marshal(req)
+
+	b.Run("json_marshal", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(size)))
+		for i := 0; i < b.N; i++ {
+			data, err := json.Marshal(req)
+			if err != nil {
+				b.Fatal(err)
+			}
+			_ = bytes.NewReader(data)
+		}
+	})
+
+	b.Run("pooled_append", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(size)))
+		for i := 0; i < b.N; i++ {
+			pb, err := newPooledBody(req)
+			if err != nil {
+				b.Fatal(err)
+			}
+			pb.Close()
+		}
+	})
+}
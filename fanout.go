@@ -0,0 +1,142 @@
+package pinecone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// defaultMaxConcurrency is used when Client.MaxConcurrency is not set.
+const defaultMaxConcurrency = 8
+
+// QueryNamespacesResponse represents the merged response from QueryNamespaces.
+type QueryNamespacesResponse struct {
+	// Matches holds the global top K across all namespaces, best first. Each
+	// match records the namespace it came from.
+	Matches []MatchResult
+
+	// Usage is the read usage summed over every successful query.
+	Usage ReadUsage
+
+	// Errors maps each namespace whose query failed to its error.
+	Errors map[string]error
+}
+
+// QueryNamespaces runs req against every namespace concurrently and merges the
+// results into a single top K, ordered according to the index metric (see
+// Client.Metric). Matches with the same ID in several namespaces are
+// collapsed to the best-scoring one.
+//
+// The namespace set on req is ignored. Failed namespaces are reported in the
+// response's Errors while the remaining results are still returned; an error
+// is returned only if every namespace failed.
+func (c *Client) QueryNamespaces(ctx context.Context, req *QueryByVectorRequest, namespaces []string) (*QueryNamespacesResponse, error) {
+	out := &QueryNamespacesResponse{}
+	if len(namespaces) == 0 {
+		return out, nil
+	}
+
+	results := make([]*QueryByVectorResponse, len(namespaces))
+	errs := make([]error, len(namespaces))
+
+	sem := make(chan struct{}, c.maxConcurrency())
+	var wg sync.WaitGroup
+	for i, ns := range namespaces {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			defer func() { <-sem }()
+
+			nsReq := *req
+			nsReq.Namespace = ns
+			results[i], errs[i] = c.QueryByVector(ctx, &nsReq)
+		}()
+	}
+	wg.Wait()
+
+	lists := make([][]MatchResult, 0, len(namespaces))
+	for i, ns := range namespaces {
+		if errs[i] != nil {
+			if out.Errors == nil {
+				out.Errors = make(map[string]error)
+			}
+			out.Errors[ns] = errs[i]
+			continue
+		}
+
+		matches := make([]MatchResult, len(results[i].Matches))
+		for j, m := range results[i].Matches {
+			m.Namespace = ns
+			matches[j] = m
+		}
+		lists = append(lists, matches)
+		out.Usage.ReadUnits += results[i].Usage.ReadUnits
+	}
+
+	out.Matches = mergeMatches(c.metric(), req.TopK, lists...)
+
+	if len(out.Errors) == len(namespaces) {
+		joined := make([]error, 0, len(namespaces))
+		for _, ns := range namespaces {
+			joined = append(joined, fmt.Errorf("namespace %q: %w", ns, out.Errors[ns]))
+		}
+		return out, errors.Join(joined...)
+	}
+	return out, nil
+}
+
+// mergeMatches combines several result lists into one, keeping the
+// best-scoring match per ID, ordered best first under metric and truncated to
+// topK when topK is positive.
+func mergeMatches(metric Metric, topK int, lists ...[]MatchResult) []MatchResult {
+	best := make(map[string]int)
+	var merged []MatchResult
+	for _, list := range lists {
+		for _, m := range list {
+			if i, ok := best[m.ID]; ok {
+				if metric.better(m.Score, merged[i].Score) {
+					merged[i] = m
+				}
+				continue
+			}
+			best[m.ID] = len(merged)
+			merged = append(merged, m)
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return metric.better(merged[i].Score, merged[j].Score)
+	})
+	if topK > 0 && len(merged) > topK {
+		merged = merged[:topK]
+	}
+	return merged
+}
+
+// maxConcurrency returns the fan-out concurrency limit.
+func (c *Client) maxConcurrency() int {
+	if c.MaxConcurrency > 0 {
+		return c.MaxConcurrency
+	}
+	return defaultMaxConcurrency
+}
+
+// metric returns c.Metric, falling back to the metric configured on
+// c.Validator and then to cosine.
+func (c *Client) metric() Metric {
+	if c.Metric != "" {
+		return c.Metric
+	}
+	if c.Validator != nil && c.Validator.Metric != "" {
+		return c.Validator.Metric
+	}
+	return MetricCosine
+}
@@ -0,0 +1,138 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fanoutTestServer answers queries with canned matches per namespace and
+// records the peak number of concurrent requests.
+func fanoutTestServer(t *testing.T, matches map[string][]MatchResult, peak *int32) *httptest.Server {
+	var inFlight int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(peak)
+			if n <= p || atomic.CompareAndSwapInt32(peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		ns := body["namespace"].(string)
+
+		list, ok := matches[ns]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"namespace not found"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(QueryByVectorResponse{
+			Matches:   list,
+			Namespace: ns,
+			Usage:     ReadUsage{ReadUnits: 5},
+		})
+	}))
+}
+
+func TestQueryNamespaces(t *testing.T) {
+	matches := map[string][]MatchResult{
+		"a": {{ID: "a1", Score: 0.9}, {ID: "dup", Score: 0.5}},
+		"b": {{ID: "dup", Score: 0.8}, {ID: "b1", Score: 0.4}},
+		"c": {{ID: "c1", Score: 0.7}},
+	}
+
+	t.Run("merges_by_score", func(t *testing.T) {
+		var peak int32
+		s := fanoutTestServer(t, matches, &peak)
+		defer s.Close()
+
+		client := NewClient(s.URL, "key")
+		client.MaxConcurrency = 2
+
+		resp, err := client.QueryNamespaces(context.Background(), &QueryByVectorRequest{Vector: []float32{1}, TopK: 3}, []string{"a", "b", "c"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []struct {
+			id, ns string
+		}{{"a1", "a"}, {"dup", "b"}, {"c1", "c"}}
+		if len(resp.Matches) != len(want) {
+			t.Fatalf("expected %d matches, got %+v", len(want), resp.Matches)
+		}
+		for i, w := range want {
+			if resp.Matches[i].ID != w.id || resp.Matches[i].Namespace != w.ns {
+				t.Errorf("match %d: expected %s from %s, got %+v", i, w.id, w.ns, resp.Matches[i])
+			}
+		}
+		if resp.Usage.ReadUnits != 15 {
+			t.Errorf("expected 15 read units, got %d", resp.Usage.ReadUnits)
+		}
+		if peak > 2 {
+			t.Errorf("expected at most 2 concurrent requests, got %d", peak)
+		}
+	})
+
+	t.Run("euclidean_lower_is_better", func(t *testing.T) {
+		var peak int32
+		s := fanoutTestServer(t, matches, &peak)
+		defer s.Close()
+
+		client := NewClient(s.URL, "key")
+		client.Metric = MetricEuclidean
+
+		resp, err := client.QueryNamespaces(context.Background(), &QueryByVectorRequest{Vector: []float32{1}, TopK: 2}, []string{"a", "b"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Matches[0].ID != "b1" || resp.Matches[1].ID != "dup" || resp.Matches[1].Namespace != "a" {
+			t.Errorf("unexpected order: %+v", resp.Matches)
+		}
+	})
+
+	t.Run("partial_failure", func(t *testing.T) {
+		var peak int32
+		s := fanoutTestServer(t, matches, &peak)
+		defer s.Close()
+
+		client := NewClient(s.URL, "key")
+		resp, err := client.QueryNamespaces(context.Background(), &QueryByVectorRequest{Vector: []float32{1}, TopK: 10}, []string{"a", "missing"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(resp.Matches) != 2 {
+			t.Errorf("expected partial results, got %+v", resp.Matches)
+		}
+		if _, ok := resp.Errors["missing"].(*APIError); !ok {
+			t.Errorf("expected APIError for missing namespace, got %v", resp.Errors)
+		}
+
+		if _, err := client.QueryNamespaces(context.Background(), &QueryByVectorRequest{Vector: []float32{1}, TopK: 10}, []string{"missing"}); err == nil {
+			t.Error("expected error when every namespace fails")
+		}
+	})
+}
+
+func TestClientMetric(t *testing.T) {
+	c := NewClient("https://example", "key")
+	if c.metric() != MetricCosine {
+		t.Errorf("expected cosine by default, got %q", c.metric())
+	}
+	c.Validator = &VectorValidator{Metric: MetricDotProduct}
+	if c.metric() != MetricDotProduct {
+		t.Errorf("expected the validator metric, got %q", c.metric())
+	}
+	c.Metric = MetricEuclidean
+	if c.metric() != MetricEuclidean {
+		t.Errorf("expected Metric to take precedence, got %q", c.metric())
+	}
+}
@@ -0,0 +1,42 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// FetchResponse represents the response from a fetch by ID.
+type FetchResponse struct {
+	Vectors   map[string]*Vector `json:"vectors"`
+	Namespace string             `json:"namespace"`
+	Usage     ReadUsage          `json:"usage"`
+}
+
+// FetchVectors looks up vectors by ID in the given namespace, returning their
+// values and metadata. IDs that do not exist are absent from the result.
+func (c *Client) FetchVectors(ctx context.Context, ids []string, namespace string) (*FetchResponse, error) {
+	params := url.Values{}
+	for _, id := range ids {
+		params.Add("ids", id)
+	}
+	params.Set("namespace", namespace)
+
+	resp, err := c.do(ctx, http.MethodGet, "/vectors/fetch?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return nil, parseAPIError(resp)
+	}
+
+	var parsed FetchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, err
+	}
+
+	return &parsed, nil
+}
@@ -0,0 +1,68 @@
+package pinecone
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchVectors(t *testing.T) {
+	t.Run("valid_fetch_response", func(t *testing.T) {
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet || r.URL.Path != "/vectors/fetch" {
+				t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+			}
+			q := r.URL.Query()
+			if ids := q["ids"]; len(ids) != 2 || ids[0] != "vec1" || ids[1] != "vec2" {
+				t.Errorf("unexpected ids: %v", ids)
+			}
+			if q.Get("namespace") != "ns" {
+				t.Errorf("unexpected namespace: %s", q.Get("namespace"))
+			}
+			w.Write([]byte(`{"vectors":{"vec1":{"id":"vec1","values":[0.1,0.2],"metadata":{"genre":"drama"}}},"namespace":"ns","usage":{"readUnits":1}}`))
+		}))
+		defer s.Close()
+
+		client := &Client{
+			IndexURL:   s.URL,
+			APIKey:     "test-key",
+			HTTPClient: s.Client(),
+		}
+
+		resp, err := client.FetchVectors(context.Background(), []string{"vec1", "vec2"}, "ns")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		v, ok := resp.Vectors["vec1"]
+		if !ok || len(resp.Vectors) != 1 {
+			t.Fatalf("unexpected vectors: %v", resp.Vectors)
+		}
+		if v.Metadata["genre"] != "drama" || len(v.Values) != 2 {
+			t.Errorf("unexpected vector: %+v", v)
+		}
+		if resp.Usage.ReadUnits != 1 {
+			t.Errorf("unexpected usage: %+v", resp.Usage)
+		}
+	})
+
+	t.Run("api_error", func(t *testing.T) {
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"namespace not found"}`))
+		}))
+		defer s.Close()
+
+		client := &Client{
+			IndexURL:   s.URL,
+			APIKey:     "test-key",
+			HTTPClient: s.Client(),
+		}
+
+		_, err := client.FetchVectors(context.Background(), []string{"vec1"}, "ns")
+		apiErr, ok := err.(*APIError)
+		if !ok || apiErr.StatusCode != http.StatusNotFound {
+			t.Fatalf("expected APIError, got %v", err)
+		}
+	})
+}
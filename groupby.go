@@ -0,0 +1,169 @@
+package pinecone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// defaultGroupRounds bounds the number of queries QueryGroups issues when
+// GroupByOptions.MaxRounds is not set.
+const defaultGroupRounds = 10
+
+// maxTopK is the largest TopK Pinecone accepts.
+const maxTopK = 10000
+
+// GroupByOptions configures QueryGroups.
+type GroupByOptions struct {
+	// Field is the metadata key to group matches by, such as "doc_id". Its
+	// values must be strings or numbers.
+	Field string
+
+	// Groups is the number of distinct groups to collect.
+	Groups int
+
+	// PerGroup is the maximum number of matches kept per group. Defaults to 1.
+	PerGroup int
+
+	// MaxReadUnits stops issuing follow-up queries once this many read units
+	// have been consumed. Zero means no budget.
+	MaxReadUnits uint32
+
+	// MaxRounds limits the number of queries issued. Defaults to 10.
+	MaxRounds int
+}
+
+// MatchGroup holds the best matches sharing a metadata value.
+type MatchGroup struct {
+	// Key is the shared value of the grouping field.
+	Key any
+
+	// Matches holds up to PerGroup matches, best first.
+	Matches []MatchResult
+}
+
+// GroupedQueryResponse represents the response from QueryGroups.
+type GroupedQueryResponse struct {
+	// Groups are ordered by their best match.
+	Groups []MatchGroup
+
+	// Usage is the read usage summed over every query issued.
+	Usage ReadUsage
+}
+
+// QueryGroups returns the best matches grouped by a metadata field, for
+// example the top documents rather than the top chunks when each document is
+// split into many vectors sharing a "doc_id".
+//
+// Each query returns req.TopK matches. When they leave groups missing or
+// short of opts.PerGroup matches, follow-up queries exclude the full groups
+// with a $nin filter, or once opts.Groups groups are known, restrict the query
+// to the short ones with $in. As the short groups' collected matches rank
+// first again, follow-up queries raise TopK by their number, up to Pinecone's
+// limit of 10,000, so each round reaches req.TopK new matches. Rounds continue until every group is full, the
+// namespace is exhausted, a round adds nothing or the round or read-unit budget
+// is reached. Matches missing the field are excluded, and a boolean value is
+// an error as $nin and $in do not support booleans. req is not modified.
+func (c *Client) QueryGroups(ctx context.Context, req *QueryByVectorRequest, opts GroupByOptions) (*GroupedQueryResponse, error) {
+	if opts.Field == "" {
+		return nil, errors.New("pinecone: group by field is required")
+	}
+	if opts.Groups <= 0 {
+		return nil, errors.New("pinecone: number of groups must be positive")
+	}
+	perGroup := opts.PerGroup
+	if perGroup <= 0 {
+		perGroup = 1
+	}
+	maxRounds := opts.MaxRounds
+	if maxRounds <= 0 {
+		maxRounds = defaultGroupRounds
+	}
+
+	out := &GroupedQueryResponse{}
+	index := make(map[any]int)
+	collected := make(map[string]bool)
+
+	for round := 0; round < maxRounds; round++ {
+		var full, short []any
+		skip := 0
+		for _, g := range out.Groups {
+			if len(g.Matches) < perGroup {
+				short = append(short, g.Key)
+				skip += len(g.Matches)
+			} else {
+				full = append(full, g.Key)
+			}
+		}
+		if len(out.Groups) >= opts.Groups && len(short) == 0 {
+			break
+		}
+
+		clauses := []map[string]any{{opts.Field: map[string]any{"$exists": true}}}
+		if req.Filter != nil {
+			clauses = append(clauses, req.Filter)
+		}
+		if len(out.Groups) >= opts.Groups {
+			clauses = append(clauses, map[string]any{opts.Field: map[string]any{"$in": short}})
+		} else if len(full) > 0 {
+			clauses = append(clauses, map[string]any{opts.Field: map[string]any{"$nin": full}})
+		}
+
+		roundReq := *req
+		roundReq.TopK = min(req.TopK+skip, maxTopK)
+		roundReq.IncludeMetadata = true
+		roundReq.Filter = map[string]any{"$and": clauses}
+
+		resp, err := c.QueryByVector(ctx, &roundReq)
+		if err != nil {
+			return out, err
+		}
+		out.Usage.ReadUnits += resp.Usage.ReadUnits
+
+		added := false
+		for _, m := range resp.Matches {
+			key, ok := groupKey(m.Metadata[opts.Field])
+			if !ok {
+				if _, isBool := m.Metadata[opts.Field].(bool); isBool {
+					return out, fmt.Errorf("pinecone: cannot group by boolean field %q", opts.Field)
+				}
+				continue
+			}
+			if collected[m.ID] {
+				continue
+			}
+			i, ok := index[key]
+			if !ok {
+				if len(out.Groups) >= opts.Groups {
+					continue
+				}
+				i = len(out.Groups)
+				index[key] = i
+				out.Groups = append(out.Groups, MatchGroup{Key: key})
+			}
+			if len(out.Groups[i].Matches) < perGroup {
+				out.Groups[i].Matches = append(out.Groups[i].Matches, m)
+				collected[m.ID] = true
+				added = true
+			}
+		}
+
+		if !added || len(resp.Matches) < roundReq.TopK {
+			break
+		}
+		if opts.MaxReadUnits > 0 && out.Usage.ReadUnits >= opts.MaxReadUnits {
+			break
+		}
+	}
+
+	return out, nil
+}
+
+// groupKey returns v as a map key if it is a supported grouping value.
+func groupKey(v any) (any, bool) {
+	switch v.(type) {
+	case string, float64:
+		return v, true
+	}
+	return nil, false
+}
@@ -0,0 +1,175 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// groupTestServer ranks a fixed set of matches, best first, applying the
+// query's filter and TopK. It records each request and bills 5 read units
+// per query.
+func groupTestServer(t *testing.T, matches []MatchResult, requests *[]QueryByVectorRequest) *httptest.Server {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req QueryByVectorRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		*requests = append(*requests, req)
+
+		resp := QueryByVectorResponse{Matches: []MatchResult{}, Usage: ReadUsage{ReadUnits: 5}}
+		for _, m := range matches {
+			if len(resp.Matches) < req.TopK && matchesFilter(m.Metadata, req.Filter) {
+				resp.Matches = append(resp.Matches, m)
+			}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// matchesFilter evaluates the subset of Pinecone's filter language used by
+// QueryGroups: $and, $exists, $in, $nin and equality.
+func matchesFilter(metadata, filter map[string]any) bool {
+	for key, cond := range filter {
+		if key == "$and" {
+			for _, clause := range cond.([]any) {
+				if !matchesFilter(metadata, clause.(map[string]any)) {
+					return false
+				}
+			}
+			continue
+		}
+
+		value, ok := metadata[key]
+		ops, isOps := cond.(map[string]any)
+		if !isOps {
+			if value != cond {
+				return false
+			}
+			continue
+		}
+		for op, arg := range ops {
+			switch op {
+			case "$exists":
+				if ok != arg.(bool) {
+					return false
+				}
+			case "$in":
+				if !slices.Contains(arg.([]any), value) {
+					return false
+				}
+			case "$nin":
+				if slices.Contains(arg.([]any), value) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+func chunk(id string, score float64, docID string) MatchResult {
+	return MatchResult{ID: id, Score: score, Metadata: map[string]any{"doc_id": docID, "lang": "en"}}
+}
+
+func TestQueryGroups(t *testing.T) {
+	ctx := context.Background()
+	docs := []MatchResult{
+		{ID: "fr#1", Score: 0.95, Metadata: map[string]any{"doc_id": "fr", "lang": "fr"}},
+		chunk("d1#1", 0.9, "d1"),
+		chunk("d1#2", 0.8, "d1"),
+		chunk("d1#3", 0.7, "d1"),
+		chunk("d2#1", 0.6, "d2"),
+		chunk("d3#1", 0.5, "d3"),
+		chunk("d2#2", 0.4, "d2"),
+	}
+
+	t.Run("collects_groups_across_rounds", func(t *testing.T) {
+		var requests []QueryByVectorRequest
+		s := groupTestServer(t, docs, &requests)
+
+		resp, err := NewClient(s.URL, "key").QueryGroups(ctx, &QueryByVectorRequest{
+			Vector: []float32{1},
+			TopK:   3,
+			Filter: map[string]any{"lang": "en"},
+		}, GroupByOptions{Field: "doc_id", Groups: 3, PerGroup: 2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(resp.Groups) != 3 {
+			t.Fatalf("expected 3 groups, got %+v", resp.Groups)
+		}
+		if resp.Groups[0].Key != "d1" || len(resp.Groups[0].Matches) != 2 {
+			t.Errorf("expected d1 capped at 2 matches, got %+v", resp.Groups[0])
+		}
+		if resp.Groups[1].Key != "d2" || len(resp.Groups[1].Matches) != 2 || resp.Groups[2].Key != "d3" {
+			t.Errorf("unexpected groups: %+v", resp.Groups[1:])
+		}
+
+		// The third round looks for more of d3, the one short group, raising
+		// TopK past its collected match, and finds no more.
+		if len(requests) != 3 || resp.Usage.ReadUnits != 15 {
+			t.Fatalf("expected 3 rounds billing 15 read units, got %d and %d", len(requests), resp.Usage.ReadUnits)
+		}
+		nin := requests[1].Filter["$and"].([]any)[2].(map[string]any)["doc_id"].(map[string]any)["$nin"].([]any)
+		if len(nin) != 1 || nin[0] != "d1" {
+			t.Errorf("expected d1 excluded in follow-up, got %v", nin)
+		}
+		in := requests[2].Filter["$and"].([]any)[2].(map[string]any)["doc_id"].(map[string]any)["$in"].([]any)
+		if len(in) != 1 || in[0] != "d3" || requests[2].TopK != 4 {
+			t.Errorf("expected the last round restricted to d3 with TopK 4, got %v and %d", in, requests[2].TopK)
+		}
+	})
+
+	t.Run("fills_short_groups", func(t *testing.T) {
+		var requests []QueryByVectorRequest
+		s := groupTestServer(t, []MatchResult{
+			chunk("d1#1", 0.9, "d1"),
+			chunk("d2#1", 0.8, "d2"),
+			chunk("d1#2", 0.7, "d1"),
+			chunk("d2#2", 0.6, "d2"),
+		}, &requests)
+
+		resp, err := NewClient(s.URL, "key").QueryGroups(ctx, &QueryByVectorRequest{Vector: []float32{1}, TopK: 2},
+			GroupByOptions{Field: "doc_id", Groups: 2, PerGroup: 2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(requests) != 2 {
+			t.Errorf("expected to stop once both groups were full, got %d rounds", len(requests))
+		}
+		for _, g := range resp.Groups {
+			if len(g.Matches) != 2 || g.Matches[0].ID == g.Matches[1].ID {
+				t.Errorf("expected 2 distinct matches in %v, got %+v", g.Key, g.Matches)
+			}
+		}
+	})
+
+	t.Run("rejects_boolean_field", func(t *testing.T) {
+		var requests []QueryByVectorRequest
+		s := groupTestServer(t, []MatchResult{{ID: "a", Score: 0.9, Metadata: map[string]any{"archived": true}}}, &requests)
+
+		if _, err := NewClient(s.URL, "key").QueryGroups(ctx, &QueryByVectorRequest{Vector: []float32{1}, TopK: 1},
+			GroupByOptions{Field: "archived", Groups: 2}); err == nil {
+			t.Error("expected an error grouping by a boolean field")
+		}
+	})
+
+	t.Run("read_unit_budget", func(t *testing.T) {
+		var requests []QueryByVectorRequest
+		s := groupTestServer(t, docs, &requests)
+
+		resp, err := NewClient(s.URL, "key").QueryGroups(ctx, &QueryByVectorRequest{Vector: []float32{1}, TopK: 3},
+			GroupByOptions{Field: "doc_id", Groups: 5, MaxReadUnits: 5})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(requests) != 1 || len(resp.Groups) != 2 {
+			t.Errorf("expected to stop after one round, got %d rounds and %d groups", len(requests), len(resp.Groups))
+		}
+	})
+}
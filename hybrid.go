@@ -0,0 +1,121 @@
+package pinecone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// defaultRRFConstant is the rank offset k used by ReciprocalRankFusion when
+// none is given, as proposed by Cormack et al.
+const defaultRRFConstant = 60
+
+// HybridScale weights a dense and a sparse query vector for hybrid search
+// using a convex combination: dense values are multiplied by alpha and sparse
+// values by 1-alpha. An alpha of 1 is a pure dense search and 0 a pure sparse
+// search.
+//
+// The inputs are not modified; scaled copies are returned.
+// See: https://docs.pinecone.io/guides/search/hybrid-search
+func HybridScale(dense []float32, sparse *SparseValues, alpha float64) ([]float32, *SparseValues, error) {
+	if alpha < 0 || alpha > 1 {
+		return nil, nil, fmt.Errorf("pinecone: hybrid alpha must be between 0 and 1, got %v", alpha)
+	}
+
+	var scaledDense []float32
+	if dense != nil {
+		scaledDense = make([]float32, len(dense))
+		for i, x := range dense {
+			scaledDense[i] = x * float32(alpha)
+		}
+	}
+
+	var scaledSparse *SparseValues
+	if sparse != nil {
+		scaledSparse = &SparseValues{
+			Indices: sparse.Indices,
+			Values:  make([]float32, len(sparse.Values)),
+		}
+		for i, x := range sparse.Values {
+			scaledSparse.Values[i] = x * float32(1-alpha)
+		}
+	}
+
+	return scaledDense, scaledSparse, nil
+}
+
+// QueryHybrid performs a hybrid search with req.Vector and req.SparseVector,
+// weighted by alpha as described in HybridScale. The index must use the
+// dotproduct metric. req is not modified.
+//
+// Example:
+//
+//	resp, err := client.QueryHybrid(ctx, &pinecone.QueryByVectorRequest{
+//	    Vector:          denseEmbedding,
+//	    SparseVector:    sparseEmbedding,
+//	    TopK:            10,
+//	    Namespace:       "example-namespace",
+//	    IncludeMetadata: true,
+//	}, 0.75)
+func (c *Client) QueryHybrid(ctx context.Context, req *QueryByVectorRequest, alpha float64) (*QueryByVectorResponse, error) {
+	if len(req.Vector) == 0 || req.SparseVector == nil {
+		return nil, errors.New("pinecone: hybrid query requires both a dense and a sparse vector")
+	}
+	if len(req.SparseVector.Indices) != len(req.SparseVector.Values) {
+		return nil, errors.New("pinecone: sparse vector indices and values differ in length")
+	}
+
+	dense, sparse, err := HybridScale(req.Vector, req.SparseVector, alpha)
+	if err != nil {
+		return nil, err
+	}
+
+	scaled := *req
+	scaled.Vector = dense
+	scaled.SparseVector = sparse
+	return c.QueryByVector(ctx, &scaled)
+}
+
+// ReciprocalRankFusion merges ranked result lists, such as those of a dense and
+// a keyword search, by reciprocal rank fusion. Each match scores the sum of
+// 1/(k+rank) over the lists it appears in, where rank starts at 1, so results
+// from searches with incomparable scores can be combined.
+//
+// The returned matches carry their fused score, best first, truncated to topK
+// when topK is positive. Values and metadata are taken from the first list in
+// which each ID appears, and usage is summed. A k of zero uses the
+// conventional constant 60.
+func ReciprocalRankFusion(k, topK int, responses ...*QueryByVectorResponse) *QueryByVectorResponse {
+	if k <= 0 {
+		k = defaultRRFConstant
+	}
+
+	out := &QueryByVectorResponse{}
+	index := make(map[string]int)
+	for _, resp := range responses {
+		if resp == nil {
+			continue
+		}
+		out.Usage.ReadUnits += resp.Usage.ReadUnits
+
+		for rank, m := range resp.Matches {
+			score := 1 / float64(k+rank+1)
+			if i, ok := index[m.ID]; ok {
+				out.Matches[i].Score += score
+				continue
+			}
+			index[m.ID] = len(out.Matches)
+			m.Score = score
+			out.Matches = append(out.Matches, m)
+		}
+	}
+
+	sort.SliceStable(out.Matches, func(i, j int) bool {
+		return out.Matches[i].Score > out.Matches[j].Score
+	})
+	if topK > 0 && len(out.Matches) > topK {
+		out.Matches = out.Matches[:topK]
+	}
+	return out
+}
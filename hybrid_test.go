@@ -0,0 +1,95 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHybridScale(t *testing.T) {
+	t.Run("convex_combination", func(t *testing.T) {
+		dense := []float32{1, 2}
+		sparse := &SparseValues{Indices: []uint32{7}, Values: []float32{4}}
+
+		d, s, err := HybridScale(dense, sparse, 0.25)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d[0] != 0.25 || d[1] != 0.5 {
+			t.Errorf("unexpected dense values: %v", d)
+		}
+		if s.Indices[0] != 7 || s.Values[0] != 3 {
+			t.Errorf("unexpected sparse values: %+v", s)
+		}
+		if dense[0] != 1 || sparse.Values[0] != 4 {
+			t.Error("expected inputs to be left unchanged")
+		}
+	})
+
+	t.Run("alpha_out_of_range", func(t *testing.T) {
+		if _, _, err := HybridScale([]float32{1}, nil, 1.5); err == nil {
+			t.Fatal("expected error for alpha > 1")
+		}
+	})
+}
+
+func TestQueryHybrid(t *testing.T) {
+	t.Run("sends_scaled_vectors", func(t *testing.T) {
+		var body struct {
+			Vector       []float32     `json:"vector"`
+			SparseVector *SparseValues `json:"sparseVector"`
+		}
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&body)
+			w.Write([]byte(`{"matches":[]}`))
+		}))
+		defer s.Close()
+
+		client := NewClient(s.URL, "key")
+		_, err := client.QueryHybrid(context.Background(), &QueryByVectorRequest{
+			Vector:       []float32{1, 1},
+			SparseVector: &SparseValues{Indices: []uint32{1}, Values: []float32{1}},
+			TopK:         5,
+		}, 0.75)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if body.Vector[0] != 0.75 || body.SparseVector.Values[0] != 0.25 {
+			t.Errorf("unexpected scaled body: %+v %+v", body.Vector, body.SparseVector)
+		}
+	})
+
+	t.Run("requires_both_vectors", func(t *testing.T) {
+		client := NewClient("https://host", "key")
+		_, err := client.QueryHybrid(context.Background(), &QueryByVectorRequest{Vector: []float32{1}}, 0.5)
+		if err == nil {
+			t.Fatal("expected error without sparse vector")
+		}
+	})
+}
+
+func TestReciprocalRankFusion(t *testing.T) {
+	dense := &QueryByVectorResponse{
+		Matches: []MatchResult{{ID: "a", Score: 0.9}, {ID: "b", Score: 0.8}},
+		Usage:   ReadUsage{ReadUnits: 5},
+	}
+	keyword := &QueryByVectorResponse{
+		Matches: []MatchResult{{ID: "b", Score: 12}, {ID: "c", Score: 7}},
+		Usage:   ReadUsage{ReadUnits: 3},
+	}
+
+	fused := ReciprocalRankFusion(0, 2, dense, keyword)
+
+	if len(fused.Matches) != 2 || fused.Matches[0].ID != "b" || fused.Matches[1].ID != "a" {
+		t.Fatalf("unexpected fused order: %+v", fused.Matches)
+	}
+	if want := 1.0/62 + 1.0/61; math.Abs(fused.Matches[0].Score-want) > 1e-12 {
+		t.Errorf("expected fused score %v, got %v", want, fused.Matches[0].Score)
+	}
+	if fused.Usage.ReadUnits != 8 {
+		t.Errorf("expected summed usage 8, got %d", fused.Usage.ReadUnits)
+	}
+}
@@ -0,0 +1,332 @@
+package pinecone
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ImportFormat identifies the encoding of a file passed to ImportFile.
+type ImportFormat int
+
+const (
+	// FormatJSONL reads one JSON-encoded Vector per line.
+	FormatJSONL ImportFormat = iota
+
+	// FormatCSV reads a header row followed by one record per row. The first
+	// column holds the vector ID, the second its values and any remaining
+	// columns are stored as string metadata keyed by their header.
+	//
+	// Values may be written as a JSON array ("[0.1,0.2]") or separated by
+	// spaces or semicolons ("0.1;0.2").
+	FormatCSV
+
+	// FormatFvecs reads the little-endian .fvecs format used by the ANN
+	// benchmarks: each record is an int32 dimension followed by that many
+	// float32 values. Records carry no IDs, so the zero-based record index is
+	// used as the vector ID.
+	FormatFvecs
+)
+
+// defaultImportBatchSize is the number of vectors sent per upsert when
+// ImportOptions.BatchSize is not set.
+const defaultImportBatchSize = 100
+
+// maxImportLineSize bounds a single JSONL line, which must hold an entire
+// vector including its values and metadata.
+const maxImportLineSize = 64 << 20
+
+// ImportOptions configures ImportFile.
+type ImportOptions struct {
+	// BatchSize is the number of vectors sent per upsert. Defaults to 100.
+	BatchSize int
+
+	// DryRun parses and validates the input without upserting anything.
+	DryRun bool
+}
+
+// ImportLineError describes a record that could not be parsed.
+type ImportLineError struct {
+	// Line is the 1-based line number for JSONL and CSV input, or the 1-based
+	// record number for fvecs input.
+	Line int
+	Err  error
+}
+
+// Error returns the string representation of the line error.
+func (e *ImportLineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying parse error.
+func (e *ImportLineError) Unwrap() error {
+	return e.Err
+}
+
+// ImportReport summarizes the outcome of an ImportFile call.
+type ImportReport struct {
+	// Parsed is the number of records parsed successfully.
+	Parsed int
+
+	// Upserted is the number of vectors Pinecone reported as upserted. It is
+	// always zero for a dry run.
+	Upserted int
+
+	// Errors lists the records that were skipped because they failed to parse.
+	Errors []*ImportLineError
+}
+
+// ImportFile streams records from r in the given format and upserts them into
+// namespace in batches.
+//
+// Records that fail to parse are collected in the report's Errors and skipped;
+// the rest of the input is still imported. An error is returned only when the
+// input cannot be read any further or an upsert fails, in which case the
+// report reflects the progress made so far.
+//
+// Example:
+//
+//	f, err := os.Open("vectors.jsonl")
+//	if err != nil {
+//	    // handle error
+//	}
+//	defer f.Close()
+//
+//	report, err := client.ImportFile(ctx, "example-namespace", f, pinecone.FormatJSONL, nil)
+func (c *Client) ImportFile(ctx context.Context, namespace string, r io.Reader, format ImportFormat, opts *ImportOptions) (*ImportReport, error) {
+	if opts == nil {
+		opts = &ImportOptions{}
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultImportBatchSize
+	}
+
+	report := &ImportReport{}
+	batch := make([]*Vector, 0, batchSize)
+
+	flush := func() error {
+		if len(batch) == 0 || opts.DryRun {
+			batch = batch[:0]
+			return nil
+		}
+		n, err := c.UpsertVectors(ctx, batch, namespace)
+		if err != nil {
+			return err
+		}
+		report.Upserted += int(n)
+		batch = batch[:0]
+		return nil
+	}
+
+	emit := func(line int, v *Vector, err error) error {
+		if err != nil {
+			report.Errors = append(report.Errors, &ImportLineError{Line: line, Err: err})
+			return nil
+		}
+		report.Parsed++
+		batch = append(batch, v)
+		if len(batch) >= batchSize {
+			return flush()
+		}
+		return nil
+	}
+
+	var err error
+	switch format {
+	case FormatJSONL:
+		err = readJSONL(r, emit)
+	case FormatCSV:
+		err = readCSV(r, emit)
+	case FormatFvecs:
+		err = readFvecs(r, emit)
+	default:
+		return nil, fmt.Errorf("pinecone: unknown import format %d", format)
+	}
+	if err != nil {
+		return report, err
+	}
+
+	if err := flush(); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// readJSONL decodes one Vector per non-blank line.
+func readJSONL(r io.Reader, emit func(int, *Vector, error) error) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxImportLineSize)
+
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			continue
+		}
+
+		var v Vector
+		err := json.Unmarshal([]byte(text), &v)
+		if err == nil {
+			err = checkImportedVector(&v)
+		}
+		if err := emit(line, &v, err); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// readCSV decodes records from a CSV file with a header row.
+func readCSV(r io.Reader, emit func(int, *Vector, error) error) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+	if len(header) < 2 {
+		return errors.New("pinecone: csv header must have at least id and values columns")
+	}
+	header = append([]string(nil), header...)
+
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			var perr *csv.ParseError
+			if !errors.As(err, &perr) {
+				return err
+			}
+			if err := emit(perr.StartLine, nil, perr.Err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		line, _ := cr.FieldPos(0)
+		v, err := parseCSVRecord(header, record)
+		if err := emit(line, v, err); err != nil {
+			return err
+		}
+	}
+}
+
+// parseCSVRecord converts a single CSV row into a Vector.
+func parseCSVRecord(header, record []string) (*Vector, error) {
+	if len(record) != len(header) {
+		return nil, fmt.Errorf("expected %d fields, got %d", len(header), len(record))
+	}
+
+	values, err := parseCSVValues(record[1])
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Vector{ID: record[0], Values: values}
+	for i := 2; i < len(record); i++ {
+		if record[i] == "" {
+			continue
+		}
+		if v.Metadata == nil {
+			v.Metadata = make(map[string]any, len(record)-2)
+		}
+		v.Metadata[header[i]] = record[i]
+	}
+
+	if err := checkImportedVector(v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// parseCSVValues parses a values cell written either as a JSON array or as a
+// list of numbers separated by spaces or semicolons.
+func parseCSVValues(s string) ([]float32, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "[") {
+		var values []float32
+		if err := json.Unmarshal([]byte(s), &values); err != nil {
+			return nil, fmt.Errorf("invalid values: %w", err)
+		}
+		return values, nil
+	}
+
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ';' || r == ' ' || r == '\t'
+	})
+	values := make([]float32, len(fields))
+	for i, f := range fields {
+		x, err := strconv.ParseFloat(f, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q", f)
+		}
+		values[i] = float32(x)
+	}
+	return values, nil
+}
+
+// maxImportDimension is Pinecone's largest dense vector dimension. It bounds
+// the allocation for each .fvecs record, whose dimension comes from the file.
+const maxImportDimension = 20000
+
+// readFvecs decodes records in the .fvecs format. A truncated record cannot be
+// skipped, so it is reported and ends the import.
+func readFvecs(r io.Reader, emit func(int, *Vector, error) error) error {
+	br := bufio.NewReader(r)
+
+	for n := 0; ; n++ {
+		var dim int32
+		if err := binary.Read(br, binary.LittleEndian, &dim); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return emit(n+1, nil, fmt.Errorf("reading dimension: %w", err))
+		}
+		if dim <= 0 || dim > maxImportDimension {
+			return emit(n+1, nil, fmt.Errorf("invalid dimension %d", dim))
+		}
+
+		values := make([]float32, dim)
+		if err := binary.Read(br, binary.LittleEndian, values); err != nil {
+			return emit(n+1, nil, fmt.Errorf("reading values: %w", err))
+		}
+
+		v := &Vector{ID: strconv.Itoa(n), Values: values}
+		if err := emit(n+1, v, checkImportedVector(v)); err != nil {
+			return err
+		}
+	}
+}
+
+// checkImportedVector rejects records that can never be upserted.
+func checkImportedVector(v *Vector) error {
+	if v.ID == "" {
+		return errors.New("missing id")
+	}
+	if len(v.Values) == 0 && v.SparseValues == nil {
+		return errors.New("missing values")
+	}
+	for _, x := range v.Values {
+		if math.IsNaN(float64(x)) || math.IsInf(float64(x), 0) {
+			return errors.New("values must be finite")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package pinecone
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// importTestClient returns a client that records the vectors of every upsert.
+func importTestClient(t *testing.T, got *[][]*Vector) *Client {
+	return &Client{
+		IndexURL: "https://example-index.svc.us-east1-gcp.io",
+		APIKey:   "test-key",
+		HTTPClient: &http.Client{
+			Transport: roundTripFunc(func(req *http.Request) *http.Response {
+				if req.URL.Path != "/vectors/upsert" {
+					t.Errorf("unexpected path: %s", req.URL.Path)
+				}
+				var payload UpsertRequest
+				if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+					t.Fatalf("decode upsert body: %v", err)
+				}
+				*got = append(*got, payload.Vectors)
+
+				data, _ := json.Marshal(UpsertResponse{UpsertedCount: uint32(len(payload.Vectors))})
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(bytes.NewReader(data)),
+					Header:     make(http.Header),
+				}
+			}),
+		},
+	}
+}
+
+func TestImportFile(t *testing.T) {
+	t.Run("jsonl_with_bad_lines", func(t *testing.T) {
+		var batches [][]*Vector
+		client := importTestClient(t, &batches)
+
+		input := strings.Join([]string{
+			`{"id":"a","values":[0.1,0.2]}`,
+			`not json`,
+			``,
+			`{"id":"b","values":[0.3,0.4],"metadata":{"genre":"drama"}}`,
+			`{"id":"","values":[0.5]}`,
+			`{"id":"c","values":[0.6,0.7]}`,
+		}, "\n")
+
+		report, err := client.ImportFile(context.Background(), "ns", strings.NewReader(input), FormatJSONL, &ImportOptions{BatchSize: 2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.Parsed != 3 || report.Upserted != 3 {
+			t.Errorf("expected 3 parsed and upserted, got %+v", report)
+		}
+		if len(batches) != 2 || len(batches[0]) != 2 || len(batches[1]) != 1 {
+			t.Errorf("unexpected batching: %d batches", len(batches))
+		}
+		if len(report.Errors) != 2 || report.Errors[0].Line != 2 || report.Errors[1].Line != 5 {
+			t.Errorf("unexpected errors: %v", report.Errors)
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var batches [][]*Vector
+		client := importTestClient(t, &batches)
+
+		input := "id,values,genre,year\n" +
+			"a,\"[0.1,0.2]\",drama,2019\n" +
+			"b,0.3;0.4,,2020\n" +
+			"c,x;y,comedy,2021\n"
+
+		report, err := client.ImportFile(context.Background(), "ns", strings.NewReader(input), FormatCSV, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.Parsed != 2 {
+			t.Fatalf("expected 2 parsed, got %d", report.Parsed)
+		}
+		if len(report.Errors) != 1 || report.Errors[0].Line != 4 {
+			t.Errorf("unexpected errors: %v", report.Errors)
+		}
+
+		got := batches[0]
+		if got[0].Metadata["genre"] != "drama" || got[0].Metadata["year"] != "2019" {
+			t.Errorf("unexpected metadata: %v", got[0].Metadata)
+		}
+		if _, ok := got[1].Metadata["genre"]; ok {
+			t.Errorf("expected empty cell to be skipped, got %v", got[1].Metadata)
+		}
+		if len(got[1].Values) != 2 || got[1].Values[1] != 0.4 {
+			t.Errorf("unexpected values: %v", got[1].Values)
+		}
+	})
+
+	t.Run("fvecs", func(t *testing.T) {
+		var buf bytes.Buffer
+		for _, vec := range [][]float32{{1, 2, 3}, {4, 5, 6}} {
+			binary.Write(&buf, binary.LittleEndian, int32(len(vec)))
+			binary.Write(&buf, binary.LittleEndian, vec)
+		}
+
+		var batches [][]*Vector
+		client := importTestClient(t, &batches)
+
+		report, err := client.ImportFile(context.Background(), "ns", &buf, FormatFvecs, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.Upserted != 2 {
+			t.Fatalf("expected 2 upserted, got %d", report.Upserted)
+		}
+		if batches[0][1].ID != "1" || batches[0][1].Values[2] != 6 {
+			t.Errorf("unexpected vector: %+v", batches[0][1])
+		}
+	})
+
+	t.Run("fvecs_truncated", func(t *testing.T) {
+		var buf bytes.Buffer
+		binary.Write(&buf, binary.LittleEndian, int32(3))
+		binary.Write(&buf, binary.LittleEndian, []float32{1, 2})
+
+		report, err := NewClient("https://host", "key").ImportFile(context.Background(), "ns", &buf, FormatFvecs, &ImportOptions{DryRun: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(report.Errors) != 1 || report.Errors[0].Line != 1 {
+			t.Errorf("expected truncated record error, got %v", report.Errors)
+		}
+	})
+
+	t.Run("fvecs_oversized_dimension", func(t *testing.T) {
+		var buf bytes.Buffer
+		binary.Write(&buf, binary.LittleEndian, int32(1<<30))
+
+		report, err := NewClient("https://host", "key").ImportFile(context.Background(), "ns", &buf, FormatFvecs, &ImportOptions{DryRun: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(report.Errors) != 1 || !strings.Contains(report.Errors[0].Error(), "invalid dimension") {
+			t.Errorf("expected an invalid dimension error, got %v", report.Errors)
+		}
+	})
+
+	t.Run("dry_run", func(t *testing.T) {
+		var batches [][]*Vector
+		client := importTestClient(t, &batches)
+
+		input := `{"id":"a","values":[0.1]}` + "\n" + `{"id":"b","values":[0.2]}`
+		report, err := client.ImportFile(context.Background(), "ns", strings.NewReader(input), FormatJSONL, &ImportOptions{DryRun: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.Parsed != 2 || report.Upserted != 0 {
+			t.Errorf("unexpected report: %+v", report)
+		}
+		if len(batches) != 0 {
+			t.Errorf("expected no upserts on dry run, got %d", len(batches))
+		}
+	})
+}
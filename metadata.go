@@ -0,0 +1,197 @@
+package pinecone
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"unicode/utf8"
+)
+
+// MaxMetadataBytes is the largest encoded metadata Pinecone accepts per record.
+const MaxMetadataBytes = 40 * 1024
+
+// MetadataValidator checks vector metadata against Pinecone's size and type
+// limits before an upsert is sent.
+//
+// Supported metadata values are strings, numbers, booleans and lists of
+// strings. Nested objects, nulls and lists of any other type are rejected.
+type MetadataValidator struct {
+	// MaxBytes is the largest encoded metadata allowed per vector. Defaults to
+	// MaxMetadataBytes.
+	MaxBytes int
+
+	// DropFields lists metadata keys that may be removed, in order, from a
+	// vector whose metadata is too large.
+	DropFields []string
+
+	// TruncateFields lists string metadata keys that may be shortened, in
+	// order, when a vector's metadata is still too large after DropFields
+	// have been removed.
+	TruncateFields []string
+}
+
+// MetadataError describes metadata rejected by a MetadataValidator.
+type MetadataError struct {
+	VectorID string
+
+	// Field is the offending metadata key, or empty when the error concerns
+	// the metadata as a whole.
+	Field string
+
+	Reason string
+}
+
+// Error returns the string representation of the metadata error.
+func (e *MetadataError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("pinecone: invalid metadata for vector %q: %s", e.VectorID, e.Reason)
+	}
+	return fmt.Sprintf("pinecone: invalid metadata field %q for vector %q: %s", e.Field, e.VectorID, e.Reason)
+}
+
+// MetadataSize returns the encoded size of metadata in bytes.
+func MetadataSize(metadata map[string]any) (int, error) {
+	if len(metadata) == 0 {
+		return 0, nil
+	}
+	b, err := json.Marshal(metadata)
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Check validates the metadata of every vector and returns the vectors to
+// send. Vectors whose metadata had to be dropped or truncated to fit are
+// replaced by modified copies; the caller's vectors are never mutated.
+//
+// It returns a *MetadataError for the first vector that cannot be made valid.
+func (m *MetadataValidator) Check(vectors []*Vector) ([]*Vector, error) {
+	var out []*Vector
+	for i, v := range vectors {
+		fixed, err := m.checkVector(v)
+		if err != nil {
+			return nil, err
+		}
+		if fixed != v && out == nil {
+			out = slices.Clone(vectors)
+		}
+		if out != nil {
+			out[i] = fixed
+		}
+	}
+	if out == nil {
+		return vectors, nil
+	}
+	return out, nil
+}
+
+// checkVector validates a single vector, returning a modified copy if its
+// metadata had to be shrunk.
+func (m *MetadataValidator) checkVector(v *Vector) (*Vector, error) {
+	if v == nil || len(v.Metadata) == 0 {
+		return v, nil
+	}
+
+	for key, value := range v.Metadata {
+		if reason := checkMetadataValue(value); reason != "" {
+			return nil, &MetadataError{VectorID: v.ID, Field: key, Reason: reason}
+		}
+	}
+
+	limit := m.MaxBytes
+	if limit <= 0 {
+		limit = MaxMetadataBytes
+	}
+
+	size, err := MetadataSize(v.Metadata)
+	if err != nil {
+		return nil, &MetadataError{VectorID: v.ID, Reason: err.Error()}
+	}
+	if size <= limit {
+		return v, nil
+	}
+
+	metadata := make(map[string]any, len(v.Metadata))
+	for key, value := range v.Metadata {
+		metadata[key] = value
+	}
+
+	for _, key := range m.DropFields {
+		if size <= limit {
+			break
+		}
+		if _, ok := metadata[key]; !ok {
+			continue
+		}
+		delete(metadata, key)
+		if size, err = MetadataSize(metadata); err != nil {
+			return nil, &MetadataError{VectorID: v.ID, Reason: err.Error()}
+		}
+	}
+
+	for _, key := range m.TruncateFields {
+		for size > limit {
+			s, ok := metadata[key].(string)
+			if !ok || s == "" {
+				break
+			}
+			metadata[key] = truncateUTF8(s, len(s)-(size-limit))
+			if size, err = MetadataSize(metadata); err != nil {
+				return nil, &MetadataError{VectorID: v.ID, Reason: err.Error()}
+			}
+		}
+	}
+
+	if size > limit {
+		return nil, &MetadataError{
+			VectorID: v.ID,
+			Reason:   fmt.Sprintf("encoded size %d bytes exceeds limit of %d bytes", size, limit),
+		}
+	}
+
+	fixed := *v
+	fixed.Metadata = metadata
+	return &fixed, nil
+}
+
+// checkMetadataValue returns the reason value is not a supported metadata
+// type, or an empty string.
+func checkMetadataValue(value any) string {
+	switch x := value.(type) {
+	case string, bool, json.Number,
+		float32, float64,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64:
+		return ""
+	case []string:
+		return ""
+	case []any:
+		for i, elem := range x {
+			if _, ok := elem.(string); !ok {
+				return fmt.Sprintf("list element %d has type %T; only lists of strings are supported", i, elem)
+			}
+		}
+		return ""
+	case nil:
+		return "null values are not supported"
+	case map[string]any:
+		return "nested objects are not supported"
+	default:
+		return fmt.Sprintf("unsupported type %T", value)
+	}
+}
+
+// truncateUTF8 shortens s to at most n bytes without splitting a rune.
+func truncateUTF8(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if n >= len(s) {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
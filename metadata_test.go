@@ -0,0 +1,100 @@
+package pinecone
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMetadataValidator(t *testing.T) {
+	t.Run("supported_types", func(t *testing.T) {
+		m := &MetadataValidator{}
+		vectors := []*Vector{{
+			ID: "a",
+			Metadata: map[string]any{
+				"s": "text", "n": 3, "f": 0.5, "b": true,
+				"tags": []string{"x"}, "any_tags": []any{"y", "z"},
+			},
+		}}
+
+		got, err := m.Check(vectors)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got[0] != vectors[0] {
+			t.Error("expected valid vector to be returned unchanged")
+		}
+	})
+
+	t.Run("unsupported_types", func(t *testing.T) {
+		m := &MetadataValidator{}
+		cases := map[string]any{
+			"nested": map[string]any{"a": 1},
+			"null":   nil,
+			"ints":   []int{1, 2},
+			"mixed":  []any{"a", 1},
+		}
+
+		for field, value := range cases {
+			_, err := m.Check([]*Vector{{ID: "v", Metadata: map[string]any{field: value}}})
+			var merr *MetadataError
+			if !errors.As(err, &merr) || merr.Field != field || merr.VectorID != "v" {
+				t.Errorf("%s: expected MetadataError, got %v", field, err)
+			}
+		}
+	})
+
+	t.Run("oversize_rejected", func(t *testing.T) {
+		m := &MetadataValidator{MaxBytes: 50}
+		_, err := m.Check([]*Vector{{ID: "v", Metadata: map[string]any{"text": strings.Repeat("a", 100)}}})
+		if err == nil || !strings.Contains(err.Error(), "exceeds limit of 50 bytes") {
+			t.Fatalf("expected size error, got %v", err)
+		}
+	})
+
+	t.Run("drop_then_truncate", func(t *testing.T) {
+		m := &MetadataValidator{
+			MaxBytes:       60,
+			DropFields:     []string{"debug"},
+			TruncateFields: []string{"text"},
+		}
+		original := &Vector{ID: "v", Metadata: map[string]any{
+			"debug": strings.Repeat("d", 40),
+			"text":  strings.Repeat("é", 40),
+			"id":    "doc-1",
+		}}
+
+		got, err := m.Check([]*Vector{original})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		fixed := got[0]
+		if _, ok := fixed.Metadata["debug"]; ok {
+			t.Error("expected debug field to be dropped")
+		}
+		if size, _ := MetadataSize(fixed.Metadata); size > 60 {
+			t.Errorf("expected size <= 60, got %d", size)
+		}
+		if text := fixed.Metadata["text"].(string); !strings.HasPrefix(strings.Repeat("é", 40), text) {
+			t.Errorf("expected rune-aligned truncation, got %q", text)
+		}
+		if _, ok := original.Metadata["debug"]; !ok {
+			t.Error("expected caller's vector to be left unchanged")
+		}
+	})
+
+	t.Run("client_rejects_before_request", func(t *testing.T) {
+		client := NewClient("http://invalid host", "key")
+		client.MetadataValidator = &MetadataValidator{}
+
+		_, err := client.UpsertVectors(context.Background(), []*Vector{
+			{ID: "v", Values: []float32{0.1}, Metadata: map[string]any{"nested": map[string]any{}}},
+		}, "ns")
+		var merr *MetadataError
+		if !errors.As(err, &merr) {
+			t.Fatalf("expected MetadataError, got %v", err)
+		}
+	})
+}
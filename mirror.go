@@ -0,0 +1,242 @@
+package pinecone
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// MirrorPolicy decides how a MirroredClient treats a write that fails on one
+// of its indexes.
+type MirrorPolicy int
+
+const (
+	// MirrorRequireBoth fails a write if either index fails it.
+	MirrorRequireBoth MirrorPolicy = iota
+
+	// MirrorRequirePrimary fails a write only if the primary fails it. Shadow
+	// failures are counted and passed to OnShadowError.
+	MirrorRequirePrimary
+)
+
+// MirroredClient writes to two indexes and reads from one, for migrating from
+// a primary index to a shadow index without downtime. Writes go to both
+// indexes concurrently. Reads are served by the primary; with ShadowReads set,
+// queries are also sent to the shadow in the background and the two result
+// sets compared.
+type MirroredClient struct {
+	Primary *Client
+	Shadow  *Client
+
+	// Policy decides whether shadow write failures fail the write. Defaults to
+	// MirrorRequireBoth.
+	Policy MirrorPolicy
+
+	// ShadowReads mirrors QueryByVector to the shadow and records the overlap
+	// of the two result sets in Stats.
+	ShadowReads bool
+
+	// OnShadowError, if set, is called with shadow failures that do not fail
+	// the call: shadow queries, and writes under MirrorRequirePrimary. op is
+	// the method name.
+	OnShadowError func(op string, err error)
+
+	mu    sync.Mutex
+	stats MirrorStats
+	wg    sync.WaitGroup
+}
+
+// MirrorStats reports how a MirroredClient's indexes compare.
+type MirrorStats struct {
+	// ShadowQueries counts queries compared against the shadow.
+	ShadowQueries uint64
+
+	// ShadowErrors counts shadow failures that did not fail the call.
+	ShadowErrors uint64
+
+	// OverlapSum is the sum of overlap@K over the compared queries, where
+	// overlap@K is the fraction of the primary's K matches the shadow also
+	// returned.
+	OverlapSum float64
+}
+
+// MeanOverlap returns the average overlap@K over the compared queries, or 1
+// if none were compared.
+func (s MirrorStats) MeanOverlap() float64 {
+	if s.ShadowQueries == 0 {
+		return 1
+	}
+	return s.OverlapSum / float64(s.ShadowQueries)
+}
+
+// NewMirroredClient returns a MirroredClient writing to primary and shadow.
+func NewMirroredClient(primary, shadow *Client) *MirroredClient {
+	return &MirroredClient{Primary: primary, Shadow: shadow}
+}
+
+// Stats returns a snapshot of the comparison counters.
+func (m *MirroredClient) Stats() MirrorStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stats
+}
+
+// Wait blocks until every background shadow query has finished.
+func (m *MirroredClient) Wait() {
+	m.wg.Wait()
+}
+
+// UpsertVectors upserts vectors into both indexes, returning the primary's
+// upserted count.
+func (m *MirroredClient) UpsertVectors(ctx context.Context, vectors []*Vector, namespace string) (uint32, error) {
+	var n uint32
+	err := m.write("UpsertVectors", func(c *Client) error {
+		count, err := c.UpsertVectors(ctx, vectors, namespace)
+		if c == m.Primary {
+			n = count
+		}
+		return err
+	})
+	return n, err
+}
+
+// DeleteVectorsByID deletes ids from both indexes.
+func (m *MirroredClient) DeleteVectorsByID(ctx context.Context, ids []string, namespace string) error {
+	return m.write("DeleteVectorsByID", func(c *Client) error {
+		return c.DeleteVectorsByID(ctx, ids, namespace)
+	})
+}
+
+// DeleteAllRecordsInNamespace deletes namespace from both indexes.
+func (m *MirroredClient) DeleteAllRecordsInNamespace(ctx context.Context, namespace string) error {
+	return m.write("DeleteAllRecordsInNamespace", func(c *Client) error {
+		return c.DeleteAllRecordsInNamespace(ctx, namespace)
+	})
+}
+
+// DeleteVectorsByMetadata deletes the vectors matching filter from both
+// indexes.
+func (m *MirroredClient) DeleteVectorsByMetadata(ctx context.Context, namespace string, filter map[string]any) error {
+	return m.write("DeleteVectorsByMetadata", func(c *Client) error {
+		return c.DeleteVectorsByMetadata(ctx, namespace, filter)
+	})
+}
+
+// QueryByVector queries the primary. With ShadowReads set, the query is also
+// sent to the shadow in the background, outliving ctx's cancellation, and the
+// overlap of the two result sets recorded.
+func (m *MirroredClient) QueryByVector(ctx context.Context, req *QueryByVectorRequest) (*QueryByVectorResponse, error) {
+	resp, err := m.Primary.QueryByVector(ctx, req)
+	if err != nil || !m.ShadowReads {
+		return resp, err
+	}
+
+	// The shadow query outlives this call, so it must not share the caller's
+	// vector, filter or MinScore, which may be reused once we return.
+	shadowReq := *req
+	shadowReq.Vector = slices.Clone(req.Vector)
+	shadowReq.SparseVector = req.SparseVector.clone()
+	if req.Filter != nil {
+		shadowReq.Filter = cloneValue(req.Filter).(map[string]any)
+	}
+	if req.MinScore != nil {
+		minScore := *req.MinScore
+		shadowReq.MinScore = &minScore
+	}
+	primaryIDs := make([]string, len(resp.Matches))
+	for i, match := range resp.Matches {
+		primaryIDs[i] = match.ID
+	}
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+
+		shadow, err := m.Shadow.QueryByVector(context.WithoutCancel(ctx), &shadowReq)
+		if err != nil {
+			m.shadowFailed("QueryByVector", err)
+			return
+		}
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.stats.ShadowQueries++
+		m.stats.OverlapSum += overlap(primaryIDs, shadow.Matches)
+	}()
+	return resp, nil
+}
+
+// FetchVectors fetches ids from the primary.
+func (m *MirroredClient) FetchVectors(ctx context.Context, ids []string, namespace string) (*FetchResponse, error) {
+	return m.Primary.FetchVectors(ctx, ids, namespace)
+}
+
+// ListVectorIDs lists IDs from the primary.
+func (m *MirroredClient) ListVectorIDs(ctx context.Context, namespace, prefix string, limit int, paginationToken string) ([]string, string, error) {
+	return m.Primary.ListVectorIDs(ctx, namespace, prefix, limit, paginationToken)
+}
+
+// write runs fn against both indexes concurrently and applies the failure
+// policy.
+func (m *MirroredClient) write(op string, fn func(c *Client) error) error {
+	var shadowErr error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		shadowErr = fn(m.Shadow)
+	}()
+	primaryErr := fn(m.Primary)
+	<-done
+
+	if primaryErr != nil {
+		primaryErr = fmt.Errorf("primary: %w", primaryErr)
+	}
+	if shadowErr == nil {
+		return primaryErr
+	}
+	shadowErr = fmt.Errorf("shadow: %w", shadowErr)
+
+	if m.Policy == MirrorRequirePrimary {
+		m.shadowFailed(op, shadowErr)
+		return primaryErr
+	}
+	if primaryErr != nil {
+		return fmt.Errorf("%w; %w", primaryErr, shadowErr)
+	}
+	return shadowErr
+}
+
+// shadowFailed records a shadow failure that does not fail the call.
+func (m *MirroredClient) shadowFailed(op string, err error) {
+	m.mu.Lock()
+	m.stats.ShadowErrors++
+	m.mu.Unlock()
+
+	if m.OnShadowError != nil {
+		m.OnShadowError(op, err)
+	}
+}
+
+// overlap returns the fraction of primaryIDs present in matches, or 1 if
+// primaryIDs is empty and matches is too.
+func overlap(primaryIDs []string, matches []MatchResult) float64 {
+	if len(primaryIDs) == 0 {
+		if len(matches) == 0 {
+			return 1
+		}
+		return 0
+	}
+
+	seen := make(map[string]bool, len(matches))
+	for _, match := range matches {
+		seen[match.ID] = true
+	}
+	hits := 0
+	for _, id := range primaryIDs {
+		if seen[id] {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(primaryIDs))
+}
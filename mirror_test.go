@@ -0,0 +1,115 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMirroredClient(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("writes_to_both", func(t *testing.T) {
+		primary, pc := newMemoryIndex(t)
+		shadow, sc := newMemoryIndex(t)
+		m := NewMirroredClient(pc, sc)
+
+		n, err := m.UpsertVectors(ctx, vectorsWithIDs("a", "b", "c"), "ns")
+		if err != nil || n != 3 {
+			t.Fatalf("upsert returned %d, %v", n, err)
+		}
+		if err := m.DeleteVectorsByID(ctx, []string{"b"}, "ns"); err != nil {
+			t.Fatalf("delete failed: %v", err)
+		}
+		for name, idx := range map[string]*memoryIndex{"primary": primary, "shadow": shadow} {
+			if got := idx.ids("ns"); len(got) != 2 {
+				t.Errorf("%s holds %v", name, got)
+			}
+		}
+	})
+
+	t.Run("failure_policy", func(t *testing.T) {
+		_, pc := newMemoryIndex(t)
+		broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer broken.Close()
+		m := NewMirroredClient(pc, NewClient(broken.URL, "key"))
+
+		if _, err := m.UpsertVectors(ctx, vectorsWithIDs("a"), "ns"); err == nil {
+			t.Error("expected MirrorRequireBoth to fail on a shadow error")
+		}
+
+		var ops []string
+		m.Policy = MirrorRequirePrimary
+		m.OnShadowError = func(op string, err error) { ops = append(ops, op) }
+		if _, err := m.UpsertVectors(ctx, vectorsWithIDs("a"), "ns"); err != nil {
+			t.Errorf("expected MirrorRequirePrimary to ignore the shadow error, got %v", err)
+		}
+		if len(ops) != 1 || ops[0] != "UpsertVectors" || m.Stats().ShadowErrors != 1 {
+			t.Errorf("unexpected shadow error reporting: %v %+v", ops, m.Stats())
+		}
+	})
+
+	t.Run("shadow_reads", func(t *testing.T) {
+		primary, pc := newMemoryIndex(t)
+		shadow, sc := newMemoryIndex(t)
+		primary.put("ns",
+			&Vector{ID: "a", Values: []float32{3}},
+			&Vector{ID: "b", Values: []float32{2}},
+			&Vector{ID: "c", Values: []float32{1}},
+		)
+		shadow.put("ns",
+			&Vector{ID: "a", Values: []float32{3}},
+			&Vector{ID: "x", Values: []float32{2}},
+			&Vector{ID: "b", Values: []float32{1}},
+		)
+		m := NewMirroredClient(pc, sc)
+		m.ShadowReads = true
+
+		resp, err := m.QueryByVector(ctx, &QueryByVectorRequest{Vector: []float32{1}, TopK: 2, Namespace: "ns"})
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		if len(resp.Matches) != 2 || resp.Matches[1].ID != "b" {
+			t.Errorf("expected the primary's results, got %+v", resp.Matches)
+		}
+
+		m.Wait()
+		stats := m.Stats()
+		if stats.ShadowQueries != 1 || stats.MeanOverlap() != 0.5 {
+			t.Errorf("expected overlap@2 of 0.5, got %+v", stats)
+		}
+	})
+
+	t.Run("shadow_query_is_copied", func(t *testing.T) {
+		_, pc := newMemoryIndex(t)
+		release := make(chan struct{})
+		received := make(chan QueryByVectorRequest, 1)
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+			var got QueryByVectorRequest
+			json.NewDecoder(r.Body).Decode(&got)
+			received <- got
+			w.Write([]byte(`{"matches":[]}`))
+		}))
+		defer s.Close()
+		m := NewMirroredClient(pc, NewClient(s.URL, "key"))
+		m.ShadowReads = true
+
+		req := &QueryByVectorRequest{Vector: []float32{1, 2}, TopK: 1, Namespace: "ns", Filter: map[string]any{"genre": "news"}}
+		if _, err := m.QueryByVector(ctx, req); err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		req.Vector[0] = 9
+		req.Filter["genre"] = "sport"
+		close(release)
+		m.Wait()
+
+		if got := <-received; got.Vector[0] != 1 || got.Filter["genre"] != "news" {
+			t.Errorf("expected the shadow to receive the original query, got %+v", got)
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package pinecone
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// mmrOverfetch is the candidate multiplier QueryMMR uses when fetchK is not
+// larger than the requested TopK.
+const mmrOverfetch = 4
+
+// MMR selects up to n matches from resp by maximal marginal relevance, trading
+// relevance to query against redundancy with the matches already selected.
+//
+// lambda ranges from 0 to 1: 1 ranks purely by relevance and 0 purely by
+// diversity. Similarities are computed with metric, which should be the
+// index's metric. Every match must carry its values, so resp must come from a
+// query with IncludeValues set.
+//
+// The returned matches keep their original scores, in selection order.
+func MMR(query []float32, resp *QueryByVectorResponse, n int, lambda float64, metric Metric) ([]MatchResult, error) {
+	if lambda < 0 || lambda > 1 {
+		return nil, fmt.Errorf("pinecone: mmr lambda must be between 0 and 1, got %v", lambda)
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("pinecone: mmr n must not be negative, got %d", n)
+	}
+
+	candidates := resp.Matches
+	for _, m := range candidates {
+		if len(m.Values) != len(query) {
+			return nil, fmt.Errorf("pinecone: match %q has %d values, expected %d; query with IncludeValues", m.ID, len(m.Values), len(query))
+		}
+	}
+	if n > len(candidates) {
+		n = len(candidates)
+	}
+
+	relevance := make([]float64, len(candidates))
+	for i, m := range candidates {
+		relevance[i] = similarity(query, m.Values, metric)
+	}
+
+	// redundancy[i] is the highest similarity between candidate i and any
+	// selected match.
+	redundancy := make([]float64, len(candidates))
+	for i := range redundancy {
+		redundancy[i] = math.Inf(-1)
+	}
+
+	selected := make([]MatchResult, 0, n)
+	used := make([]bool, len(candidates))
+	for len(selected) < n {
+		best, bestScore := -1, math.Inf(-1)
+		for i := range candidates {
+			if used[i] {
+				continue
+			}
+			score := lambda * relevance[i]
+			if len(selected) > 0 {
+				score -= (1 - lambda) * redundancy[i]
+			}
+			if best < 0 || score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+
+		used[best] = true
+		selected = append(selected, candidates[best])
+		for i := range candidates {
+			if !used[i] {
+				redundancy[i] = math.Max(redundancy[i], similarity(candidates[i].Values, candidates[best].Values, metric))
+			}
+		}
+	}
+	return selected, nil
+}
+
+// QueryMMR queries fetchK candidates and returns the req.TopK most relevant yet
+// diverse among them, as selected by MMR using the index metric (see
+// Client.Metric). If fetchK is not larger than req.TopK, four times TopK
+// candidates are fetched.
+//
+// Values are always requested from Pinecone, but are only included in the
+// result if req.IncludeValues is set. req is not modified.
+func (c *Client) QueryMMR(ctx context.Context, req *QueryByVectorRequest, fetchK int, lambda float64) (*QueryByVectorResponse, error) {
+	if fetchK <= req.TopK {
+		fetchK = req.TopK * mmrOverfetch
+	}
+
+	candidates := *req
+	candidates.TopK = fetchK
+	candidates.IncludeValues = true
+
+	resp, err := c.QueryByVector(ctx, &candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := MMR(req.Vector, resp, req.TopK, lambda, c.metric())
+	if err != nil {
+		return nil, err
+	}
+	if !req.IncludeValues {
+		for i := range matches {
+			matches[i].Values = nil
+		}
+	}
+
+	resp.Matches = matches
+	return resp, nil
+}
+
+// similarity scores a against b under metric, higher meaning more similar.
+// Euclidean similarity is the negated squared distance.
+func similarity(a, b []float32, metric Metric) float64 {
+	switch metric {
+	case MetricEuclidean:
+		var d float64
+		for i := range a {
+			diff := float64(a[i]) - float64(b[i])
+			d += diff * diff
+		}
+		return -d
+	case MetricDotProduct:
+		var dot float64
+		for i := range a {
+			dot += float64(a[i]) * float64(b[i])
+		}
+		return dot
+	default:
+		var dot, na, nb float64
+		for i := range a {
+			dot += float64(a[i]) * float64(b[i])
+			na += float64(a[i]) * float64(a[i])
+			nb += float64(b[i]) * float64(b[i])
+		}
+		if na == 0 || nb == 0 {
+			return 0
+		}
+		return dot / math.Sqrt(na*nb)
+	}
+}
@@ -0,0 +1,83 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMMR(t *testing.T) {
+	query := []float32{1, 0}
+	resp := &QueryByVectorResponse{Matches: []MatchResult{
+		{ID: "a", Score: 0.99, Values: []float32{1, 0.05}},
+		{ID: "a-dup", Score: 0.98, Values: []float32{1, 0.06}},
+		{ID: "b", Score: 0.70, Values: []float32{0.7, 0.7}},
+	}}
+
+	t.Run("pure_relevance", func(t *testing.T) {
+		got, err := MMR(query, resp, 2, 1, MetricCosine)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got[0].ID != "a" || got[1].ID != "a-dup" {
+			t.Errorf("unexpected selection: %v, %v", got[0].ID, got[1].ID)
+		}
+	})
+
+	t.Run("diversified", func(t *testing.T) {
+		got, err := MMR(query, resp, 2, 0.3, MetricCosine)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got[0].ID != "a" || got[1].ID != "b" {
+			t.Errorf("expected near-duplicate to be skipped, got %v, %v", got[0].ID, got[1].ID)
+		}
+		if got[1].Score != 0.70 {
+			t.Errorf("expected original score to be kept, got %v", got[1].Score)
+		}
+	})
+
+	t.Run("requires_values", func(t *testing.T) {
+		_, err := MMR(query, &QueryByVectorResponse{Matches: []MatchResult{{ID: "x"}}}, 1, 0.5, MetricCosine)
+		if err == nil {
+			t.Fatal("expected error for match without values")
+		}
+	})
+
+	t.Run("negative_n", func(t *testing.T) {
+		if _, err := MMR(query, resp, -1, 0.5, MetricCosine); err == nil {
+			t.Fatal("expected error for negative n")
+		}
+	})
+}
+
+func TestQueryMMR(t *testing.T) {
+	var body map[string]any
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"matches":[
+			{"id":"a","score":0.99,"values":[1,0.05]},
+			{"id":"a-dup","score":0.98,"values":[1,0.06]},
+			{"id":"b","score":0.7,"values":[0.7,0.7]}
+		]}`))
+	}))
+	defer s.Close()
+
+	client := NewClient(s.URL, "key")
+	resp, err := client.QueryMMR(context.Background(), &QueryByVectorRequest{Vector: []float32{1, 0}, TopK: 2}, 0, 0.3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if body["topK"] != float64(8) || body["includeValues"] != true {
+		t.Errorf("expected over-fetch with values, got %v", body)
+	}
+	if len(resp.Matches) != 2 || resp.Matches[1].ID != "b" {
+		t.Fatalf("unexpected matches: %+v", resp.Matches)
+	}
+	if resp.Matches[0].Values != nil {
+		t.Error("expected values to be stripped when not requested")
+	}
+}
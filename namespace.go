@@ -0,0 +1,263 @@
+package pinecone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrTenantViolation is returned by a tenant view for requests that would
+// read or write another tenant's data.
+var ErrTenantViolation = errors.New("pinecone: tenant violation")
+
+// NamespaceClient is a view of a store bound to a single namespace, so callers
+// cannot pass the wrong one. Create one with Client.Namespace or
+// NewNamespaceClient, and narrow it to a tenant with Tenant.
+type NamespaceClient struct {
+	store     VectorStore
+	namespace string
+
+	// tenantField and tenantID are set on tenant views.
+	tenantField string
+	tenantID    string
+}
+
+// NewNamespaceClient returns a view of s bound to namespace.
+func NewNamespaceClient(s VectorStore, namespace string) *NamespaceClient {
+	return &NamespaceClient{store: s, namespace: namespace}
+}
+
+// Namespace returns a view of c bound to namespace.
+func (c *Client) Namespace(namespace string) *NamespaceClient {
+	return NewNamespaceClient(c, namespace)
+}
+
+// Name returns the namespace the view is bound to.
+func (n *NamespaceClient) Name() string {
+	return n.namespace
+}
+
+// Tenant returns a view that only sees vectors whose metadata field is id,
+// for namespaces shared by several tenants. The view:
+//
+//   - adds field to upserted vectors that lack it, and refuses vectors set to
+//     another tenant or whose IDs another tenant already owns;
+//   - restricts queries and metadata deletes with a mandatory filter on
+//     field, refusing filters that mention field themselves;
+//   - drops other tenants' vectors from fetches and listings, and never
+//     deletes them by ID;
+//   - implements DeleteAll as a delete of the tenant's vectors only.
+//
+// Requests it refuses fail with ErrTenantViolation. Tenant panics if field or
+// id is empty, since such a view would enforce nothing.
+func (n *NamespaceClient) Tenant(field, id string) *NamespaceClient {
+	if field == "" || id == "" {
+		panic("pinecone: Tenant requires a metadata field and a tenant ID")
+	}
+	t := *n
+	t.tenantField, t.tenantID = field, id
+	return &t
+}
+
+// UpsertVectors upserts vectors into the view's namespace. A tenant view first
+// fetches the IDs, refusing the whole upsert if another tenant owns any of
+// them. The check and the upsert are separate requests, so an ID another
+// tenant claims in between is not detected.
+func (n *NamespaceClient) UpsertVectors(ctx context.Context, vectors []*Vector) (uint32, error) {
+	if n.tenantField != "" {
+		tagged := make([]*Vector, len(vectors))
+		for i, v := range vectors {
+			if v == nil {
+				return 0, fmt.Errorf("pinecone: vector %d is nil", i)
+			}
+			switch value, ok := v.Metadata[n.tenantField]; {
+			case !ok:
+				tagged[i] = withMetadataField(v, n.tenantField, n.tenantID)
+			case value != n.tenantID:
+				return 0, fmt.Errorf("%w: vector %q has %s %v", ErrTenantViolation, v.ID, n.tenantField, value)
+			default:
+				tagged[i] = v
+			}
+		}
+		vectors = tagged
+
+		ids := make([]string, len(vectors))
+		for i, v := range vectors {
+			ids[i] = v.ID
+		}
+		err := n.eachFetched(ctx, ids, func(id string, v *Vector) error {
+			if v != nil && !n.owns(v) {
+				return fmt.Errorf("%w: vector %q belongs to another tenant", ErrTenantViolation, id)
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return n.store.UpsertVectors(ctx, vectors, n.namespace)
+}
+
+// QueryByVector queries the view's namespace. req.Namespace must be empty or
+// name the view's namespace.
+func (n *NamespaceClient) QueryByVector(ctx context.Context, req *QueryByVectorRequest) (*QueryByVectorResponse, error) {
+	if req == nil {
+		return nil, errors.New("pinecone: query request is required")
+	}
+	if req.Namespace != "" && req.Namespace != n.namespace {
+		return nil, fmt.Errorf("%w: %q", ErrNamespaceNotAllowed, req.Namespace)
+	}
+	filter, err := n.scopeFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	scoped := *req
+	scoped.Namespace = n.namespace
+	scoped.Filter = filter
+	return n.store.QueryByVector(ctx, &scoped)
+}
+
+// FetchVectors fetches ids from the view's namespace.
+func (n *NamespaceClient) FetchVectors(ctx context.Context, ids []string) (*FetchResponse, error) {
+	resp, err := n.store.FetchVectors(ctx, ids, n.namespace)
+	if err != nil || n.tenantField == "" {
+		return resp, err
+	}
+	for id, v := range resp.Vectors {
+		if !n.owns(v) {
+			delete(resp.Vectors, id)
+		}
+	}
+	return resp, nil
+}
+
+// ListVectorIDs lists IDs in the view's namespace. On a tenant view, each page
+// is fetched to drop other tenants' IDs, so pages may hold fewer than limit
+// IDs.
+func (n *NamespaceClient) ListVectorIDs(ctx context.Context, prefix string, limit int, paginationToken string) ([]string, string, error) {
+	ids, next, err := n.store.ListVectorIDs(ctx, n.namespace, prefix, limit, paginationToken)
+	if err != nil || n.tenantField == "" {
+		return ids, next, err
+	}
+	if ids, err = n.ownedIDs(ctx, ids); err != nil {
+		return nil, "", err
+	}
+	return ids, next, nil
+}
+
+// DeleteVectorsByID deletes ids from the view's namespace. A tenant view first
+// fetches the IDs and deletes only the tenant's own vectors.
+func (n *NamespaceClient) DeleteVectorsByID(ctx context.Context, ids []string) error {
+	if n.tenantField != "" {
+		var err error
+		if ids, err = n.ownedIDs(ctx, ids); err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+	}
+	return n.store.DeleteVectorsByID(ctx, ids, n.namespace)
+}
+
+// DeleteVectorsByMetadata deletes the vectors matching filter from the view's
+// namespace.
+func (n *NamespaceClient) DeleteVectorsByMetadata(ctx context.Context, filter map[string]any) error {
+	filter, err := n.scopeFilter(filter)
+	if err != nil {
+		return err
+	}
+	return n.store.DeleteVectorsByMetadata(ctx, n.namespace, filter)
+}
+
+// DeleteAll deletes every vector in the view's namespace. On a tenant view it
+// deletes only the tenant's vectors.
+func (n *NamespaceClient) DeleteAll(ctx context.Context) error {
+	if n.tenantField != "" {
+		return n.DeleteVectorsByMetadata(ctx, nil)
+	}
+	return n.store.DeleteAllRecordsInNamespace(ctx, n.namespace)
+}
+
+// scopeFilter adds the tenant condition to filter on tenant views.
+func (n *NamespaceClient) scopeFilter(filter map[string]any) (map[string]any, error) {
+	if n.tenantField == "" {
+		return filter, nil
+	}
+	if mentionsField(filter, n.tenantField) {
+		return nil, fmt.Errorf("%w: filter must not reference %s", ErrTenantViolation, n.tenantField)
+	}
+
+	tenant := map[string]any{n.tenantField: map[string]any{"$eq": n.tenantID}}
+	if len(filter) == 0 {
+		return tenant, nil
+	}
+	return map[string]any{"$and": []any{tenant, filter}}, nil
+}
+
+// owns reports whether v belongs to the view's tenant.
+func (n *NamespaceClient) owns(v *Vector) bool {
+	return v != nil && v.Metadata[n.tenantField] == n.tenantID
+}
+
+// ownedIDs returns the IDs among ids that exist and belong to the view's
+// tenant, preserving their order.
+func (n *NamespaceClient) ownedIDs(ctx context.Context, ids []string) ([]string, error) {
+	var owned []string
+	err := n.eachFetched(ctx, ids, func(id string, v *Vector) error {
+		if n.owns(v) {
+			owned = append(owned, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return owned, nil
+}
+
+// eachFetched fetches ids from the view's namespace in batches and calls fn
+// with each ID in order and its vector, or nil if it does not exist. It stops
+// at the first error.
+func (n *NamespaceClient) eachFetched(ctx context.Context, ids []string, fn func(id string, v *Vector) error) error {
+	for start := 0; start < len(ids); start += maxFetchIDs {
+		batch := ids[start:min(start+maxFetchIDs, len(ids))]
+		resp, err := n.store.FetchVectors(ctx, batch, n.namespace)
+		if err != nil {
+			return err
+		}
+		for _, id := range batch {
+			if err := fn(id, resp.Vectors[id]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mentionsField reports whether filter constrains field anywhere, including
+// inside $and and $or clauses.
+func mentionsField(filter any, field string) bool {
+	switch f := filter.(type) {
+	case map[string]any:
+		for k, v := range f {
+			if k == field || mentionsField(v, field) {
+				return true
+			}
+		}
+	case []any:
+		for _, v := range f {
+			if mentionsField(v, field) {
+				return true
+			}
+		}
+	case []map[string]any:
+		for _, v := range f {
+			if mentionsField(v, field) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestNamespaceClient(t *testing.T) {
+	ctx := context.Background()
+	m, c := newMemoryIndex(t)
+	docs := c.Namespace("docs")
+
+	if docs.Name() != "docs" {
+		t.Errorf("unexpected name %q", docs.Name())
+	}
+	if _, err := docs.UpsertVectors(ctx, vectorsWithIDs("a", "b")); err != nil {
+		t.Fatalf("upsert failed: %v", err)
+	}
+	if !slices.Equal(m.ids("docs"), []string{"a", "b"}) {
+		t.Errorf("expected vectors in the bound namespace, got %v", m.ids("docs"))
+	}
+
+	if _, err := docs.QueryByVector(ctx, &QueryByVectorRequest{Vector: []float32{1}, TopK: 1, Namespace: "other"}); !errors.Is(err, ErrNamespaceNotAllowed) {
+		t.Errorf("expected a conflicting namespace to be refused, got %v", err)
+	}
+	resp, err := docs.QueryByVector(ctx, &QueryByVectorRequest{Vector: []float32{1}, TopK: 5})
+	if err != nil || len(resp.Matches) != 2 {
+		t.Errorf("unexpected query result: %+v, %v", resp, err)
+	}
+
+	if err := docs.DeleteAll(ctx); err != nil {
+		t.Fatalf("delete all failed: %v", err)
+	}
+	if len(m.ids("docs")) != 0 {
+		t.Error("expected the namespace to be deleted")
+	}
+}
+
+func TestTenantView(t *testing.T) {
+	ctx := context.Background()
+	m, c := newMemoryIndex(t)
+	m.put("shared",
+		&Vector{ID: "theirs", Values: []float32{1}, Metadata: map[string]any{"tenant_id": "globex"}},
+	)
+	acme := c.Namespace("shared").Tenant("tenant_id", "acme")
+
+	if _, err := acme.UpsertVectors(ctx, vectorsWithIDs("a", "b")); err != nil {
+		t.Fatalf("upsert failed: %v", err)
+	}
+	if m.namespaces["shared"]["a"].Metadata["tenant_id"] != "acme" {
+		t.Error("expected upserted vectors to be tagged with the tenant")
+	}
+	foreign := &Vector{ID: "x", Values: []float32{1}, Metadata: map[string]any{"tenant_id": "globex"}}
+	if _, err := acme.UpsertVectors(ctx, []*Vector{foreign}); !errors.Is(err, ErrTenantViolation) {
+		t.Errorf("expected another tenant's vector to be refused, got %v", err)
+	}
+	overwrite := &Vector{ID: "theirs", Values: []float32{2}}
+	if _, err := acme.UpsertVectors(ctx, []*Vector{overwrite}); !errors.Is(err, ErrTenantViolation) {
+		t.Errorf("expected overwriting another tenant's ID to be refused, got %v", err)
+	}
+	if v := m.namespaces["shared"]["theirs"]; v.Metadata["tenant_id"] != "globex" || v.Values[0] != 1 {
+		t.Errorf("another tenant's vector was overwritten: %+v", v)
+	}
+	if _, err := acme.UpsertVectors(ctx, []*Vector{{ID: "a", Values: []float32{3}}}); err != nil {
+		t.Errorf("expected the tenant to overwrite its own vector, got %v", err)
+	}
+	if _, err := acme.QueryByVector(ctx, nil); err == nil {
+		t.Error("expected an error for a nil query request")
+	}
+	if _, err := acme.UpsertVectors(ctx, []*Vector{nil}); err == nil {
+		t.Error("expected an error for a nil vector")
+	}
+
+	if _, err := acme.QueryByVector(ctx, &QueryByVectorRequest{Vector: []float32{1}, TopK: 5, Filter: map[string]any{"genre": "news"}}); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	got, _ := json.Marshal(m.filters[len(m.filters)-1])
+	want := `{"$and":[{"tenant_id":{"$eq":"acme"}},{"genre":"news"}]}`
+	if string(got) != want {
+		t.Errorf("unexpected query filter %s, want %s", got, want)
+	}
+
+	override := map[string]any{"$or": []any{map[string]any{"tenant_id": "globex"}}}
+	if _, err := acme.QueryByVector(ctx, &QueryByVectorRequest{Vector: []float32{1}, TopK: 5, Filter: override}); !errors.Is(err, ErrTenantViolation) {
+		t.Errorf("expected a filter on the tenant field to be refused, got %v", err)
+	}
+	if err := acme.DeleteVectorsByMetadata(ctx, override); !errors.Is(err, ErrTenantViolation) {
+		t.Errorf("expected a delete filter on the tenant field to be refused, got %v", err)
+	}
+
+	fetched, err := acme.FetchVectors(ctx, []string{"a", "theirs"})
+	if err != nil || len(fetched.Vectors) != 1 || fetched.Vectors["a"] == nil {
+		t.Errorf("expected only the tenant's vector to be fetched, got %+v, %v", fetched, err)
+	}
+
+	var listed []string
+	token := ""
+	for {
+		ids, next, err := acme.ListVectorIDs(ctx, "", 0, token)
+		if err != nil {
+			t.Fatalf("list failed: %v", err)
+		}
+		listed = append(listed, ids...)
+		if next == "" {
+			break
+		}
+		token = next
+	}
+	if !slices.Equal(listed, []string{"a", "b"}) {
+		t.Errorf("expected only the tenant's IDs, got %v", listed)
+	}
+
+	if err := acme.DeleteVectorsByID(ctx, []string{"a", "theirs"}); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if !slices.Equal(m.ids("shared"), []string{"b", "theirs"}) {
+		t.Errorf("expected only the tenant's vector to be deleted, got %v", m.ids("shared"))
+	}
+
+	if err := acme.DeleteAll(ctx); err != nil {
+		t.Fatalf("delete all failed: %v", err)
+	}
+	got, _ = json.Marshal(m.filters[len(m.filters)-1])
+	if string(got) != `{"tenant_id":{"$eq":"acme"}}` {
+		t.Errorf("expected DeleteAll to delete by the tenant filter, got %s", got)
+	}
+	if len(m.ids("shared")) == 0 {
+		t.Error("tenant DeleteAll removed the whole namespace")
+	}
+}
+
+func TestTenantRequiresFieldAndID(t *testing.T) {
+	for _, tc := range []struct{ field, id string }{{"", "acme"}, {"tenant_id", ""}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected Tenant(%q, %q) to panic", tc.field, tc.id)
+				}
+			}()
+			NewClient("https://example", "key").Namespace("ns").Tenant(tc.field, tc.id)
+		}()
+	}
+}
@@ -2,27 +2,52 @@ package pinecone
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"net/http"
+	"net/url"
 	"strconv"
 )
 
 // MatchResult represents a result match returned from a query.
 type MatchResult struct {
-	ID       string         `json:"id"`
-	Score    float64        `json:"score"`
-	Values   []float32      `json:"values,omitempty"`
-	Metadata map[string]any `json:"metadata,omitempty"`
+	ID           string         `json:"id"`
+	Score        float64        `json:"score"`
+	Values       []float32      `json:"values,omitempty"`
+	SparseValues *SparseValues  `json:"sparseValues,omitempty"`
+	Metadata     map[string]any `json:"metadata,omitempty"`
+
+	// Namespace is the namespace the match came from. It is set by helpers that
+	// merge results across namespaces and is empty otherwise.
+	Namespace string `json:"namespace,omitempty"`
 }
 
-// QueryByVectorRequest represents a request to query similar vectors.
+// QueryByVectorRequest represents a request to query similar vectors. Use
+// NewQueryByVectorRequest to query with a float64 embedding.
 type QueryByVectorRequest struct {
-	Vector          []float64
+	Vector []float32
+
+	// SparseVector, if set, is sent alongside Vector for hybrid search on a
+	// dotproduct index, or on its own for a sparse index.
+	SparseVector *SparseValues
+
 	TopK            int
 	Namespace       string
 	Filter          map[string]any
 	IncludeValues   bool
 	IncludeMetadata bool
+
+	// MinScore, if set, drops matches scoring worse than it under the index
+	// metric (see Client.Metric): below it for cosine and dotproduct, above
+	// it for euclidean, where scores are distances. It is applied on the
+	// client and not sent to Pinecone.
+	MinScore *float64
+}
+
+// NewQueryByVectorRequest returns a request for the topK vectors nearest to
+// vector in namespace, converting vector to float32 if it is float64.
+func NewQueryByVectorRequest[T ~float32 | ~float64](vector []T, topK int, namespace string) *QueryByVectorRequest {
+	return &QueryByVectorRequest{Vector: Float32s(vector), TopK: topK, Namespace: namespace}
 }
 
 // QueryByVectorResponse represents the response from a vector query.
@@ -38,15 +63,44 @@ type ReadUsage struct {
 }
 
 // QueryByVector performs a similarity search using a dense vector.
+//
+// If c.Validator is set, the query vector is checked before the request is sent.
+// If c.Cache is set, responses are served from and stored in the cache.
 func (c *Client) QueryByVector(ctx context.Context, req *QueryByVectorRequest) (*QueryByVectorResponse, error) {
+	sparseOnly := len(req.Vector) == 0 && req.SparseVector != nil
+	if !sparseOnly {
+		if err := c.validateQuery(req.Vector); err != nil {
+			return nil, err
+		}
+	}
+
+	var cacheKey [sha256.Size]byte
+	var cacheGen uint64
+	if c.Cache != nil {
+		var err error
+		if cacheKey, err = queryCacheKey(req); err != nil {
+			return nil, err
+		}
+		var cached *QueryByVectorResponse
+		if cached, cacheGen = c.Cache.get(cacheKey, req.Namespace); cached != nil {
+			return cached, nil
+		}
+	}
+
 	body := map[string]any{
-		"vector":          req.Vector,
 		"topK":            req.TopK,
 		"namespace":       req.Namespace,
 		"includeValues":   req.IncludeValues,
 		"includeMetadata": req.IncludeMetadata,
 	}
 
+	if !sparseOnly {
+		body["vector"] = req.Vector
+	}
+	if req.SparseVector != nil {
+		body["sparseVector"] = req.SparseVector
+	}
+
 	if req.Filter != nil {
 		body["filter"] = req.Filter
 	}
@@ -66,6 +120,21 @@ func (c *Client) QueryByVector(ctx context.Context, req *QueryByVectorRequest) (
 		return nil, err
 	}
 
+	if req.MinScore != nil {
+		metric := c.metric()
+		kept := parsed.Matches[:0]
+		for _, m := range parsed.Matches {
+			if !metric.better(*req.MinScore, m.Score) {
+				kept = append(kept, m)
+			}
+		}
+		parsed.Matches = kept
+	}
+
+	if c.Cache != nil {
+		c.Cache.put(cacheKey, req.Namespace, cacheGen, &parsed)
+	}
+
 	return &parsed, nil
 }
 
@@ -97,20 +166,19 @@ func (c *Client) QueryByVector(ctx context.Context, req *QueryByVectorRequest) (
 //	// If nextToken is not empty, retrieve the next page:
 //	// moreIDs, _, err := client.ListVectors(ctx, "production", "", 100, nextToken)
 func (c *Client) ListVectorIDs(ctx context.Context, namespace, prefix string, limit int, paginationToken string) ([]string, string, error) {
-	params := map[string]string{
-		"namespace": namespace,
-	}
+	params := url.Values{}
+	params.Set("namespace", namespace)
 	if prefix != "" {
-		params["prefix"] = prefix
+		params.Set("prefix", prefix)
 	}
 	if limit > 0 {
-		params["limit"] = strconv.Itoa(limit)
+		params.Set("limit", strconv.Itoa(limit))
 	}
 	if paginationToken != "" {
-		params["paginationToken"] = paginationToken
+		params.Set("paginationToken", paginationToken)
 	}
 
-	resp, err := c.do(ctx, http.MethodGet, "/vectors/list", params)
+	resp, err := c.do(ctx, http.MethodGet, "/vectors/list?"+params.Encode(), nil)
 	if err != nil {
 		return nil, "", err
 	}
@@ -55,7 +55,7 @@ func TestQueryByVectors(t *testing.T) {
 		}
 
 		req := &QueryByVectorRequest{
-			Vector:          []float64{0.1, 0.2, 0.3},
+			Vector:          []float32{0.1, 0.2, 0.3},
 			TopK:            1,
 			Namespace:       "test-namespace",
 			Filter:          filter,
@@ -93,11 +93,13 @@ func TestListVectors(t *testing.T) {
 
 		data, _ := json.Marshal(mockResponse)
 
+		var listReq *http.Request
 		client := &Client{
 			IndexURL: "https://example-index.svc.us-east1-gcp.io",
 			APIKey:   "test-key",
 			HTTPClient: &http.Client{
 				Transport: roundTripFunc(func(req *http.Request) *http.Response {
+					listReq = req
 					return &http.Response{
 						StatusCode: 200,
 						Body:       io.NopCloser(bytes.NewReader(data)),
@@ -120,5 +122,51 @@ func TestListVectors(t *testing.T) {
 		if nextToken != "next-token" {
 			t.Errorf("expected nextToken 'next-token', got %s", nextToken)
 		}
+		if q := listReq.URL.Query(); q.Get("namespace") != "production" || q.Get("limit") != "100" || listReq.Body != nil {
+			t.Errorf("expected parameters in the query string, got %s with body %v", listReq.URL, listReq.Body)
+		}
+	})
+}
+
+func TestQueryMinScore(t *testing.T) {
+	data := []byte(`{"matches":[{"id":"a","score":0.9},{"id":"b","score":0.5},{"id":"c","score":0.2}]}`)
+	newClient := func() *Client {
+		return &Client{
+			IndexURL: "https://example-index.svc.us-east1-gcp.io",
+			APIKey:   "test-key",
+			HTTPClient: &http.Client{
+				Transport: roundTripFunc(func(req *http.Request) *http.Response {
+					return &http.Response{
+						StatusCode: 200,
+						Body:       io.NopCloser(bytes.NewReader(data)),
+						Header:     make(http.Header),
+					}
+				}),
+			},
+		}
+	}
+	minScore := 0.5
+
+	t.Run("similarity_metric", func(t *testing.T) {
+		resp, err := newClient().QueryByVector(context.Background(), &QueryByVectorRequest{Vector: []float32{1}, TopK: 3, MinScore: &minScore})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(resp.Matches) != 2 || resp.Matches[1].ID != "b" {
+			t.Errorf("expected matches scoring at least 0.5, got %+v", resp.Matches)
+		}
+	})
+
+	t.Run("euclidean_metric", func(t *testing.T) {
+		client := newClient()
+		client.Validator = &VectorValidator{Metric: MetricEuclidean}
+
+		resp, err := client.QueryByVector(context.Background(), &QueryByVectorRequest{Vector: []float32{1}, TopK: 3, MinScore: &minScore})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(resp.Matches) != 2 || resp.Matches[0].ID != "b" || resp.Matches[1].ID != "c" {
+			t.Errorf("expected distances of at most 0.5, got %+v", resp.Matches)
+		}
 	})
 }
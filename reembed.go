@@ -0,0 +1,163 @@
+package pinecone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Embedder computes one embedding per text, in order.
+type Embedder func(ctx context.Context, texts []string) ([][]float32, error)
+
+// ReembedOptions configures ReembedNamespace.
+type ReembedOptions struct {
+	// TextField is the metadata field holding each vector's source text.
+	// Vectors without a non-empty string in this field are skipped.
+	TextField string
+
+	// BatchSize is the number of vectors listed, embedded and upserted at a
+	// time. Defaults to 100.
+	BatchSize int
+
+	// Throttle, if positive, is the minimum time between embedder calls, to
+	// stay within the embedding provider's rate limits.
+	Throttle time.Duration
+
+	// CheckpointPath, if set, records progress in this file so an interrupted
+	// migration resumes where it stopped. The file is removed once the
+	// migration completes.
+	CheckpointPath string
+
+	// OnProgress, if set, is called after each batch is upserted.
+	OnProgress func(ReembedReport)
+}
+
+// ReembedReport describes the progress of ReembedNamespace, including work
+// done before resuming from a checkpoint.
+type ReembedReport struct {
+	// Embedded counts the vectors re-embedded and upserted.
+	Embedded int
+
+	// Skipped counts the vectors without source text.
+	Skipped int
+}
+
+// ReembedNamespace recomputes every vector in src from the source text stored
+// in its metadata and upserts the result, with the original metadata and
+// sparse values, into dst. dst may be src itself to re-embed in place, or a
+// namespace in a new index sized for the new model.
+func ReembedNamespace(ctx context.Context, src, dst NamespaceRef, embed Embedder, opts *ReembedOptions) (*ReembedReport, error) {
+	if opts == nil || opts.TextField == "" {
+		return nil, errors.New("pinecone: re-embedding requires a text field")
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 || batchSize > maxFetchIDs {
+		batchSize = maxFetchIDs
+	}
+
+	cp, err := resumeCheckpoint(opts.CheckpointPath, src.Client.IndexURL+"/"+src.Namespace, dst.Client.IndexURL+"/"+dst.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	report := &ReembedReport{Embedded: cp.Copied, Skipped: cp.Skipped}
+
+	var last time.Time
+	for !cp.Done {
+		ids, next, err := src.Client.ListVectorIDs(ctx, src.Namespace, "", batchSize, cp.Token)
+		if err != nil {
+			return report, err
+		}
+
+		if opts.Throttle > 0 && !last.IsZero() {
+			if err := sleepContext(ctx, opts.Throttle-time.Since(last)); err != nil {
+				return report, err
+			}
+		}
+		last = time.Now()
+
+		embedded, skipped, err := reembedBatch(ctx, src, dst, ids, embed, opts.TextField)
+		if err != nil {
+			return report, err
+		}
+
+		cp.Token, cp.Done = next, next == ""
+		cp.Copied += embedded
+		cp.Skipped += skipped
+		if opts.CheckpointPath != "" {
+			if err := saveCheckpoint(opts.CheckpointPath, cp); err != nil {
+				return report, err
+			}
+		}
+
+		report.Embedded, report.Skipped = cp.Copied, cp.Skipped
+		if opts.OnProgress != nil {
+			opts.OnProgress(*report)
+		}
+	}
+
+	return report, removeCheckpoint(opts.CheckpointPath)
+}
+
+// reembedBatch re-embeds the vectors with the given IDs, returning how many
+// were upserted and how many lacked source text.
+func reembedBatch(ctx context.Context, src, dst NamespaceRef, ids []string, embed Embedder, textField string) (int, int, error) {
+	if len(ids) == 0 {
+		return 0, 0, nil
+	}
+	resp, err := src.Client.FetchVectors(ctx, ids, src.Namespace)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var vectors []*Vector
+	var texts []string
+	skipped := 0
+	for _, id := range ids {
+		v, ok := resp.Vectors[id]
+		if !ok {
+			continue
+		}
+		text, _ := v.Metadata[textField].(string)
+		if text == "" {
+			skipped++
+			continue
+		}
+		vectors = append(vectors, v)
+		texts = append(texts, text)
+	}
+	if len(vectors) == 0 {
+		return 0, skipped, nil
+	}
+
+	embeddings, err := embed(ctx, texts)
+	if err != nil {
+		return 0, skipped, err
+	}
+	if len(embeddings) != len(texts) {
+		return 0, skipped, fmt.Errorf("pinecone: embedder returned %d embeddings for %d texts", len(embeddings), len(texts))
+	}
+	for i, v := range vectors {
+		v.Values = embeddings[i]
+	}
+
+	if _, err := dst.Client.UpsertVectors(ctx, vectors, dst.Namespace); err != nil {
+		return 0, skipped, err
+	}
+	return len(vectors), skipped, nil
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,109 @@
+package pinecone
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReembedNamespace(t *testing.T) {
+	ctx := context.Background()
+	embed := func(ctx context.Context, texts []string) ([][]float32, error) {
+		out := make([][]float32, len(texts))
+		for i, text := range texts {
+			out[i] = []float32{float32(len(text)), 0, 1}
+		}
+		return out, nil
+	}
+	seed := func(m *memoryIndex) {
+		m.put("docs",
+			&Vector{ID: "a", Values: []float32{1}, Metadata: map[string]any{"text": "hello", "lang": "en"}},
+			&Vector{ID: "b", Values: []float32{1}, Metadata: map[string]any{"text": "hi"}},
+			&Vector{ID: "c", Values: []float32{1}},
+			&Vector{ID: "d", Values: []float32{1}, Metadata: map[string]any{"text": "hey there"}},
+		)
+	}
+
+	t.Run("into_new_index", func(t *testing.T) {
+		srcIndex, srcClient := newMemoryIndex(t)
+		dstIndex, dstClient := newMemoryIndex(t)
+		seed(srcIndex)
+
+		var progress []ReembedReport
+		report, err := ReembedNamespace(ctx,
+			NamespaceRef{Client: srcClient, Namespace: "docs"},
+			NamespaceRef{Client: dstClient, Namespace: "docs-v2"},
+			embed,
+			&ReembedOptions{
+				TextField:  "text",
+				BatchSize:  2,
+				Throttle:   time.Millisecond,
+				OnProgress: func(r ReembedReport) { progress = append(progress, r) },
+			})
+		if err != nil {
+			t.Fatalf("re-embed failed: %v", err)
+		}
+		if report.Embedded != 3 || report.Skipped != 1 {
+			t.Errorf("unexpected report: %+v", report)
+		}
+		if len(progress) != 2 || progress[1] != *report {
+			t.Errorf("unexpected progress: %+v", progress)
+		}
+
+		a := dstIndex.namespaces["docs-v2"]["a"]
+		if len(a.Values) != 3 || a.Values[0] != 5 || a.Metadata["lang"] != "en" {
+			t.Errorf("unexpected re-embedded vector: %+v", a)
+		}
+		if _, ok := dstIndex.namespaces["docs-v2"]["c"]; ok {
+			t.Error("expected the vector without text to be skipped")
+		}
+	})
+
+	t.Run("resumes_from_checkpoint", func(t *testing.T) {
+		m, c := newMemoryIndex(t)
+		seed(m)
+		ref := NamespaceRef{Client: c, Namespace: "docs"}
+		opts := &ReembedOptions{TextField: "text", BatchSize: 2, CheckpointPath: filepath.Join(t.TempDir(), "reembed.json")}
+
+		boom := errors.New("rate limited")
+		_, err := ReembedNamespace(ctx, ref, ref, func(ctx context.Context, texts []string) ([][]float32, error) {
+			for _, text := range texts {
+				if strings.HasPrefix(text, "hey") {
+					return nil, boom
+				}
+			}
+			return embed(ctx, texts)
+		}, opts)
+		if !errors.Is(err, boom) {
+			t.Fatalf("expected the embedder error, got %v", err)
+		}
+
+		var calls int
+		report, err := ReembedNamespace(ctx, ref, ref, func(ctx context.Context, texts []string) ([][]float32, error) {
+			calls++
+			return embed(ctx, texts)
+		}, opts)
+		if err != nil {
+			t.Fatalf("resumed re-embed failed: %v", err)
+		}
+		if calls != 1 || report.Embedded != 3 || report.Skipped != 1 {
+			t.Errorf("expected only the failed batch to be redone, got %d calls and %+v", calls, report)
+		}
+	})
+
+	t.Run("embedding_count_mismatch", func(t *testing.T) {
+		m, c := newMemoryIndex(t)
+		seed(m)
+		ref := NamespaceRef{Client: c, Namespace: "docs"}
+
+		_, err := ReembedNamespace(ctx, ref, ref, func(ctx context.Context, texts []string) ([][]float32, error) {
+			return nil, nil
+		}, &ReembedOptions{TextField: "text"})
+		if err == nil {
+			t.Error("expected an error when the embedder returns too few embeddings")
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package pinecone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// maxFilterValues is the most values Pinecone accepts in a single $in or $nin
+// filter operator.
+const maxFilterValues = 10000
+
+// ErrSearchLimit is returned by SearchIterator.Err when another page would
+// need to exclude more IDs than a $nin filter accepts.
+var ErrSearchLimit = fmt.Errorf("pinecone: search cannot exclude more than %d IDs", maxFilterValues)
+
+// SearchIterator pages through query results beyond a single TopK, yielding
+// matches in score order. Create one with Client.Search.
+//
+// Example:
+//
+//	it := client.Search(&pinecone.QueryByVectorRequest{
+//	    Vector:    embedding,
+//	    TopK:      100,
+//	    Namespace: "example-namespace",
+//	    MinScore:  &minScore,
+//	}, "id")
+//	for it.Next(ctx) {
+//	    m := it.Match()
+//	    // use m
+//	}
+//	if err := it.Err(); err != nil {
+//	    // handle error
+//	}
+type SearchIterator struct {
+	c       *Client
+	req     QueryByVectorRequest
+	idField string
+
+	seen []any
+	ids  map[string]bool
+	page []MatchResult
+	cur  MatchResult
+	done bool
+	err  error
+
+	// maxExcluded caps len(seen) for a follow-up query.
+	maxExcluded int
+
+	usage ReadUsage
+}
+
+// Search returns an iterator over every match for req, fetched req.TopK at a
+// time. Each follow-up query excludes the IDs already returned with a $nin
+// filter on idField, so every vector must store its own ID in that metadata
+// field. Iteration ends when a page comes back short, which happens when the
+// namespace is exhausted or req.MinScore is crossed.
+//
+// Because a $nin filter holds at most 10,000 values, a search yields at most
+// 10,000 + req.TopK matches. Past that, Next returns false and Err returns
+// ErrSearchLimit; narrow the query with a filter or MinScore to go further.
+//
+// req is copied; later changes to it do not affect the iterator.
+func (c *Client) Search(req *QueryByVectorRequest, idField string) *SearchIterator {
+	it := &SearchIterator{
+		c:           c,
+		req:         *req,
+		idField:     idField,
+		ids:         make(map[string]bool),
+		maxExcluded: maxFilterValues,
+	}
+	switch {
+	case idField == "":
+		it.err = errors.New("pinecone: search requires an ID metadata field")
+	case req.TopK <= 0:
+		it.err = errors.New("pinecone: search requires a positive TopK")
+	}
+	return it
+}
+
+// Next advances to the next match, fetching another page when needed. It
+// returns false when iteration is complete or an error occurred.
+func (it *SearchIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if len(it.page) == 0 {
+		if it.done {
+			return false
+		}
+		if err := it.fetch(ctx); err != nil {
+			it.err = err
+			return false
+		}
+		if len(it.page) == 0 {
+			return false
+		}
+	}
+
+	it.cur = it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+// Match returns the current match.
+func (it *SearchIterator) Match() MatchResult {
+	return it.cur
+}
+
+// Err returns the first error encountered during iteration.
+func (it *SearchIterator) Err() error {
+	return it.err
+}
+
+// Usage returns the read usage of every query issued so far.
+func (it *SearchIterator) Usage() ReadUsage {
+	return it.usage
+}
+
+// fetch queries the next page, excluding every ID already seen.
+func (it *SearchIterator) fetch(ctx context.Context) error {
+	if len(it.seen) > it.maxExcluded {
+		return ErrSearchLimit
+	}
+
+	req := it.req
+	if len(it.seen) > 0 {
+		exclude := map[string]any{it.idField: map[string]any{"$nin": it.seen}}
+		if req.Filter != nil {
+			req.Filter = map[string]any{"$and": []map[string]any{req.Filter, exclude}}
+		} else {
+			req.Filter = exclude
+		}
+	}
+
+	resp, err := it.c.QueryByVector(ctx, &req)
+	if err != nil {
+		return err
+	}
+	it.usage.ReadUnits += resp.Usage.ReadUnits
+
+	if len(resp.Matches) < req.TopK {
+		it.done = true
+	}
+
+	for _, m := range resp.Matches {
+		if it.ids[m.ID] {
+			continue
+		}
+		it.ids[m.ID] = true
+		it.seen = append(it.seen, m.ID)
+		it.page = append(it.page, m)
+	}
+
+	if len(it.page) == 0 && !it.done {
+		return fmt.Errorf("pinecone: search returned only seen IDs; check that every vector stores its ID in metadata field %q", it.idField)
+	}
+	return nil
+}
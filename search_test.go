@@ -0,0 +1,117 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// searchTestServer serves matches from a fixed, score-ordered pool, honouring
+// $nin exclusions on the "id" metadata field.
+func searchTestServer(t *testing.T, pool []MatchResult, requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		var body struct {
+			TopK   int            `json:"topK"`
+			Filter map[string]any `json:"filter"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		excluded := map[string]bool{}
+		if f, ok := body.Filter["id"].(map[string]any); ok {
+			for _, id := range f["$nin"].([]any) {
+				excluded[id.(string)] = true
+			}
+		}
+
+		var page []MatchResult
+		for _, m := range pool {
+			if !excluded[m.ID] && len(page) < body.TopK {
+				page = append(page, m)
+			}
+		}
+		json.NewEncoder(w).Encode(QueryByVectorResponse{Matches: page, Usage: ReadUsage{ReadUnits: 1}})
+	}))
+}
+
+func TestSearch(t *testing.T) {
+	pool := []MatchResult{
+		{ID: "a", Score: 0.9}, {ID: "b", Score: 0.8}, {ID: "c", Score: 0.7},
+		{ID: "d", Score: 0.6}, {ID: "e", Score: 0.5},
+	}
+
+	t.Run("pages_until_exhausted", func(t *testing.T) {
+		var requests int
+		s := searchTestServer(t, pool, &requests)
+		defer s.Close()
+
+		it := NewClient(s.URL, "key").Search(&QueryByVectorRequest{Vector: []float32{1}, TopK: 2}, "id")
+
+		var ids []string
+		for it.Next(context.Background()) {
+			ids = append(ids, it.Match().ID)
+		}
+		if err := it.Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(ids) != 5 || ids[0] != "a" || ids[4] != "e" {
+			t.Errorf("unexpected order: %v", ids)
+		}
+		if requests != 3 || it.Usage().ReadUnits != 3 {
+			t.Errorf("expected 3 pages, got %d requests", requests)
+		}
+	})
+
+	t.Run("stops_at_min_score", func(t *testing.T) {
+		var requests int
+		s := searchTestServer(t, pool, &requests)
+		defer s.Close()
+
+		minScore := 0.75
+		it := NewClient(s.URL, "key").Search(&QueryByVectorRequest{Vector: []float32{1}, TopK: 2, MinScore: &minScore}, "id")
+
+		var ids []string
+		for it.Next(context.Background()) {
+			ids = append(ids, it.Match().ID)
+		}
+		if len(ids) != 2 || requests != 2 {
+			t.Errorf("expected a and b over 2 requests, got %v over %d", ids, requests)
+		}
+	})
+
+	t.Run("no_progress", func(t *testing.T) {
+		var requests int
+		s := searchTestServer(t, pool, &requests)
+		defer s.Close()
+
+		it := NewClient(s.URL, "key").Search(&QueryByVectorRequest{Vector: []float32{1}, TopK: 2}, "doc_id")
+		for it.Next(context.Background()) {
+		}
+		if it.Err() == nil {
+			t.Fatal("expected error when exclusions have no effect")
+		}
+	})
+
+	t.Run("exclusion_limit", func(t *testing.T) {
+		var requests int
+		s := searchTestServer(t, pool, &requests)
+		defer s.Close()
+
+		it := NewClient(s.URL, "key").Search(&QueryByVectorRequest{Vector: []float32{1}, TopK: 2}, "id")
+		it.maxExcluded = 3
+
+		var ids []string
+		for it.Next(context.Background()) {
+			ids = append(ids, it.Match().ID)
+		}
+		if !errors.Is(it.Err(), ErrSearchLimit) {
+			t.Fatalf("expected ErrSearchLimit, got %v", it.Err())
+		}
+		if len(ids) != 4 || requests != 2 {
+			t.Errorf("expected 4 matches over 2 requests before the limit, got %v over %d", ids, requests)
+		}
+	})
+}
@@ -0,0 +1,202 @@
+// Package semcache implements a semantic cache for LLM responses on top of a
+// Pinecone index.
+//
+// Each entry stores a prompt embedding as a vector, with the prompt, answer
+// and expiry time as metadata. A lookup returns the stored answer of the most
+// similar earlier prompt when its similarity reaches the configured threshold.
+// Namespaces keep tenants' entries apart.
+//
+// Example:
+//
+//	cache := semcache.New(client, semcache.Options{Threshold: 0.95, TTL: time.Hour})
+//
+//	entry, err := cache.Lookup(ctx, "tenant-a", embedding)
+//	if err != nil {
+//	    // handle error
+//	}
+//	if entry == nil {
+//	    answer := callLLM(prompt)
+//	    err = cache.Store(ctx, "tenant-a", prompt, answer, embedding)
+//	}
+package semcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	pinecone "github.com/qhenkart/pinecone-lite"
+)
+
+// Metadata keys used for cache entries.
+const (
+	FieldPrompt    = "prompt"
+	FieldAnswer    = "answer"
+	FieldExpiresAt = "expires_at"
+)
+
+// DefaultThreshold is the similarity a lookup must reach when
+// Options.Threshold is not set.
+const DefaultThreshold = 0.95
+
+// noExpiry is stored for entries without a TTL: 9999-12-31T23:59:59Z.
+const noExpiry = 253402300799
+
+// Options configures a Cache.
+type Options struct {
+	// Threshold is the minimum similarity score for a lookup to hit. It
+	// assumes a cosine or dotproduct index, where higher scores are more
+	// similar. Defaults to DefaultThreshold.
+	Threshold float64
+
+	// TTL is how long entries stored with Store remain valid. Zero means
+	// entries never expire.
+	TTL time.Duration
+}
+
+// Entry is a cached prompt and answer.
+type Entry struct {
+	ID        string
+	Prompt    string
+	Answer    string
+	Score     float64
+	ExpiresAt time.Time
+}
+
+// Cache is a semantic cache backed by a Pinecone index.
+type Cache struct {
+	client    *pinecone.Client
+	threshold float64
+	ttl       time.Duration
+
+	now func() time.Time
+}
+
+// New returns a Cache that stores entries through client.
+func New(client *pinecone.Client, opts Options) *Cache {
+	threshold := opts.Threshold
+	if threshold == 0 {
+		threshold = DefaultThreshold
+	}
+	return &Cache{
+		client:    client,
+		threshold: threshold,
+		ttl:       opts.TTL,
+		now:       time.Now,
+	}
+}
+
+// Lookup returns the unexpired entry in namespace whose prompt is most similar
+// to embedding, or nil if none reaches the threshold.
+func (c *Cache) Lookup(ctx context.Context, namespace string, embedding []float32) (*Entry, error) {
+	now := c.now()
+	resp, err := c.client.QueryByVector(ctx, &pinecone.QueryByVectorRequest{
+		Vector:          embedding,
+		TopK:            1,
+		Namespace:       namespace,
+		Filter:          map[string]any{FieldExpiresAt: map[string]any{"$gt": now.Unix()}},
+		IncludeMetadata: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Matches) == 0 {
+		return nil, nil
+	}
+
+	m := resp.Matches[0]
+	if m.Score < c.threshold {
+		return nil, nil
+	}
+
+	entry := &Entry{ID: m.ID, Score: m.Score}
+	entry.Prompt, _ = m.Metadata[FieldPrompt].(string)
+	entry.Answer, _ = m.Metadata[FieldAnswer].(string)
+	if exp, ok := m.Metadata[FieldExpiresAt].(float64); ok {
+		entry.ExpiresAt = time.Unix(int64(exp), 0)
+		if !entry.ExpiresAt.After(now) {
+			return nil, nil
+		}
+	}
+	return entry, nil
+}
+
+// Store caches answer for prompt in namespace using the cache's default TTL.
+// Storing the same prompt again replaces its entry.
+func (c *Cache) Store(ctx context.Context, namespace, prompt, answer string, embedding []float32) error {
+	return c.StoreWithTTL(ctx, namespace, prompt, answer, embedding, c.ttl)
+}
+
+// StoreWithTTL caches answer for prompt in namespace for ttl. A zero ttl
+// stores an entry that never expires.
+func (c *Cache) StoreWithTTL(ctx context.Context, namespace, prompt, answer string, embedding []float32, ttl time.Duration) error {
+	expiresAt := int64(noExpiry)
+	if ttl > 0 {
+		expiresAt = c.now().Add(ttl).Unix()
+	}
+
+	_, err := c.client.UpsertVectors(ctx, []*pinecone.Vector{{
+		ID:     EntryID(prompt),
+		Values: embedding,
+		Metadata: map[string]any{
+			FieldPrompt:    prompt,
+			FieldAnswer:    answer,
+			FieldExpiresAt: expiresAt,
+		},
+	}}, namespace)
+	return err
+}
+
+// Sweep deletes the expired entries in namespace.
+func (c *Cache) Sweep(ctx context.Context, namespace string) error {
+	return c.client.DeleteVectorsByMetadata(ctx, namespace, map[string]any{
+		FieldExpiresAt: map[string]any{"$lte": c.now().Unix()},
+	})
+}
+
+// StartSweeper sweeps namespaces every interval in a background goroutine
+// until ctx is done or the returned stop function is called. stop waits for
+// an in-progress sweep to finish. Sweep errors are passed to onError if it is
+// non-nil. interval must be positive.
+func (c *Cache) StartSweeper(ctx context.Context, interval time.Duration, namespaces []string, onError func(namespace string, err error)) (stop func(), err error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("semcache: sweep interval must be positive, got %v", interval)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			for _, ns := range namespaces {
+				if err := c.Sweep(ctx, ns); err != nil && onError != nil && ctx.Err() == nil {
+					onError(ns, err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		wg.Wait()
+	}, nil
+}
+
+// EntryID returns the vector ID used for prompt.
+func EntryID(prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,135 @@
+package semcache
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	pinecone "github.com/qhenkart/pinecone-lite"
+)
+
+// fakeIndex records requests and answers queries with a canned match.
+type fakeIndex struct {
+	mu       sync.Mutex
+	bodies   map[string][]map[string]any
+	response string
+}
+
+func (f *fakeIndex) handler(w http.ResponseWriter, r *http.Request) {
+	var body map[string]any
+	json.NewDecoder(r.Body).Decode(&body)
+
+	f.mu.Lock()
+	f.bodies[r.URL.Path] = append(f.bodies[r.URL.Path], body)
+	f.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/query":
+		w.Write([]byte(f.response))
+	case "/vectors/upsert":
+		w.Write([]byte(`{"upsertedCount":1}`))
+	}
+}
+
+func (f *fakeIndex) requests(path string) []map[string]any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.bodies[path]
+}
+
+func newTestCache(t *testing.T, response string, opts Options) (*Cache, *fakeIndex) {
+	f := &fakeIndex{bodies: make(map[string][]map[string]any), response: response}
+	s := httptest.NewServer(http.HandlerFunc(f.handler))
+	t.Cleanup(s.Close)
+
+	c := New(pinecone.NewClient(s.URL, "key"), opts)
+	c.now = func() time.Time { return time.Unix(1000, 0) }
+	return c, f
+}
+
+func TestLookup(t *testing.T) {
+	t.Run("hit", func(t *testing.T) {
+		c, f := newTestCache(t, `{"matches":[{"id":"x","score":0.97,"metadata":{"prompt":"hi","answer":"hello","expires_at":2000}}]}`, Options{})
+
+		entry, err := c.Lookup(context.Background(), "tenant", []float32{0.1})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if entry == nil || entry.Answer != "hello" || entry.Prompt != "hi" || entry.ExpiresAt.Unix() != 2000 {
+			t.Fatalf("unexpected entry: %+v", entry)
+		}
+
+		req := f.requests("/query")[0]
+		if req["namespace"] != "tenant" {
+			t.Errorf("expected tenant namespace, got %v", req["namespace"])
+		}
+		filter := req["filter"].(map[string]any)[FieldExpiresAt].(map[string]any)
+		if filter["$gt"] != float64(1000) {
+			t.Errorf("expected expiry filter, got %v", filter)
+		}
+	})
+
+	t.Run("below_threshold", func(t *testing.T) {
+		c, _ := newTestCache(t, `{"matches":[{"id":"x","score":0.90,"metadata":{"answer":"hello"}}]}`, Options{})
+
+		entry, err := c.Lookup(context.Background(), "tenant", []float32{0.1})
+		if err != nil || entry != nil {
+			t.Fatalf("expected miss, got %+v, %v", entry, err)
+		}
+	})
+}
+
+func TestStore(t *testing.T) {
+	c, f := newTestCache(t, `{}`, Options{TTL: time.Minute})
+
+	if err := c.Store(context.Background(), "tenant", "hi", "hello", []float32{0.1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.StoreWithTTL(context.Background(), "tenant", "hi", "hello", []float32{0.1}, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reqs := f.requests("/vectors/upsert")
+	first := reqs[0]["vectors"].([]any)[0].(map[string]any)
+	if first["id"] != EntryID("hi") {
+		t.Errorf("expected prompt-derived ID, got %v", first["id"])
+	}
+	if exp := first["metadata"].(map[string]any)[FieldExpiresAt]; exp != float64(1060) {
+		t.Errorf("expected expiry 1060, got %v", exp)
+	}
+	second := reqs[1]["vectors"].([]any)[0].(map[string]any)
+	if exp := second["metadata"].(map[string]any)[FieldExpiresAt]; exp != float64(noExpiry) {
+		t.Errorf("expected no expiry, got %v", exp)
+	}
+}
+
+func TestSweeper(t *testing.T) {
+	c, f := newTestCache(t, `{}`, Options{})
+
+	if _, err := c.StartSweeper(context.Background(), 0, []string{"a"}, nil); err == nil {
+		t.Error("expected an error for a zero interval")
+	}
+
+	stop, err := c.StartSweeper(context.Background(), 5*time.Millisecond, []string{"a", "b"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for len(f.requests("/vectors/delete")) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	stop()
+
+	reqs := f.requests("/vectors/delete")
+	if len(reqs) < 2 {
+		t.Fatalf("expected sweeps of both namespaces, got %d", len(reqs))
+	}
+	filter := reqs[0]["filter"].(map[string]any)[FieldExpiresAt].(map[string]any)
+	if reqs[0]["namespace"] != "a" || filter["$lte"] != float64(1000) {
+		t.Errorf("unexpected sweep request: %v", reqs[0])
+	}
+}
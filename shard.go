@@ -0,0 +1,388 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// AllShards is returned by a Router when a key does not determine a single
+// shard, so the operation must go to every shard.
+const AllShards = -1
+
+// errNoShards is returned by ShardedClient methods called without shards.
+var errNoShards = errors.New("pinecone: sharded client has no shards")
+
+// RouteKey describes what a ShardedClient knows about the vectors an operation
+// touches. ID and Metadata are only set when known: upserts carry all three,
+// fetches and deletes by ID carry the namespace and ID, and queries and
+// metadata deletes carry the namespace and the plain equality conditions of
+// their filter as Metadata.
+type RouteKey struct {
+	Namespace string
+	ID        string
+	Metadata  map[string]any
+}
+
+// Router picks the index of the shard holding the vectors described by key, or
+// AllShards.
+type Router func(key RouteKey) int
+
+// NamespacePrefixRouter routes by the longest prefix in prefixes that the
+// namespace starts with. Namespaces matching no prefix go to every shard, and
+// cannot be upserted.
+func NamespacePrefixRouter(prefixes map[string]int) Router {
+	return func(key RouteKey) int {
+		shard, longest := AllShards, -1
+		for prefix, i := range prefixes {
+			if len(prefix) > longest && strings.HasPrefix(key.Namespace, prefix) {
+				shard, longest = i, len(prefix)
+			}
+		}
+		return shard
+	}
+}
+
+// HashRouter spreads vectors over n shards by a consistent hash of their ID,
+// so growing n moves only about 1/n of the vectors. Operations without an ID
+// go to every shard.
+func HashRouter(n int) Router {
+	return func(key RouteKey) int {
+		if key.ID == "" {
+			return AllShards
+		}
+		h := fnv.New64a()
+		h.Write([]byte(key.ID))
+		return jumpHash(h.Sum64(), n)
+	}
+}
+
+// MetadataRouter routes by the string value of a metadata field, looked up in
+// shards. Operations whose key lacks a known value go to every shard, and
+// cannot be upserted.
+func MetadataRouter(field string, shards map[string]int) Router {
+	return func(key RouteKey) int {
+		value, ok := key.Metadata[field].(string)
+		if !ok {
+			return AllShards
+		}
+		if i, ok := shards[value]; ok {
+			return i
+		}
+		return AllShards
+	}
+}
+
+// jumpHash is Lamping and Veach's jump consistent hash, mapping key to one of
+// n buckets.
+func jumpHash(key uint64, n int) int {
+	var b, j int64 = -1, 0
+	for j < int64(n) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
+// ShardedClient spreads an index's data over several indexes, for example one
+// per region for data residency. Writes and fetches go to the shard chosen by
+// Route; queries fan out to every relevant shard and are merged by score.
+//
+// All shards are expected to share a dimension and metric; the metric of the
+// first shard (see Client.Metric) orders merged query results.
+type ShardedClient struct {
+	Shards []*Client
+	Route  Router
+
+	// MaxConcurrency limits the number of shards queried concurrently.
+	// Defaults to 8.
+	MaxConcurrency int
+}
+
+// NewShardedClient returns a ShardedClient routing between shards with route.
+func NewShardedClient(route Router, shards ...*Client) *ShardedClient {
+	return &ShardedClient{Shards: shards, Route: route}
+}
+
+// UpsertVectors upserts each vector into its shard, returning the total
+// upserted count. Every vector must route to a single shard.
+func (s *ShardedClient) UpsertVectors(ctx context.Context, vectors []*Vector, namespace string) (uint32, error) {
+	groups := make(map[int][]*Vector)
+	for _, v := range vectors {
+		i := s.Route(RouteKey{Namespace: namespace, ID: v.ID, Metadata: v.Metadata})
+		if i < 0 || i >= len(s.Shards) {
+			return 0, fmt.Errorf("pinecone: vector %q in namespace %q does not route to a shard", v.ID, namespace)
+		}
+		groups[i] = append(groups[i], v)
+	}
+
+	var mu sync.Mutex
+	var total uint32
+	err := s.each(ctx, shardKeys(groups), func(i int, c *Client) error {
+		n, err := c.UpsertVectors(ctx, groups[i], namespace)
+		mu.Lock()
+		total += n
+		mu.Unlock()
+		return err
+	})
+	return total, err
+}
+
+// QueryByVector queries every shard relevant to the request and merges the
+// results into a single top K. It fails if any shard fails, rather than return
+// results silently missing a shard.
+func (s *ShardedClient) QueryByVector(ctx context.Context, req *QueryByVectorRequest) (*QueryByVectorResponse, error) {
+	if len(s.Shards) == 0 {
+		return nil, errNoShards
+	}
+	shards := s.shardsFor(RouteKey{Namespace: req.Namespace, Metadata: filterEqualities(req.Filter)})
+
+	results := make([]*QueryByVectorResponse, len(s.Shards))
+	err := s.each(ctx, shards, func(i int, c *Client) error {
+		var err error
+		results[i], err = c.QueryByVector(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := &QueryByVectorResponse{Namespace: req.Namespace}
+	lists := make([][]MatchResult, 0, len(shards))
+	for _, i := range shards {
+		lists = append(lists, results[i].Matches)
+		out.Usage.ReadUnits += results[i].Usage.ReadUnits
+	}
+	out.Matches = mergeMatches(s.Shards[0].metric(), req.TopK, lists...)
+	return out, nil
+}
+
+// FetchVectors fetches each ID from its shard, or from every shard when the
+// ID alone does not determine one.
+func (s *ShardedClient) FetchVectors(ctx context.Context, ids []string, namespace string) (*FetchResponse, error) {
+	groups := s.groupIDs(ids, namespace)
+
+	var mu sync.Mutex
+	out := &FetchResponse{Vectors: make(map[string]*Vector), Namespace: namespace}
+	err := s.each(ctx, shardKeys(groups), func(i int, c *Client) error {
+		resp, err := c.FetchVectors(ctx, groups[i], namespace)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for id, v := range resp.Vectors {
+			out.Vectors[id] = v
+		}
+		out.Usage.ReadUnits += resp.Usage.ReadUnits
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListVectorIDs lists the IDs in namespace across its shards, one shard after
+// another. The pagination token records the shard being listed, so it must
+// only be passed back to the same ShardedClient configuration. A page may be
+// empty while later shards remain.
+func (s *ShardedClient) ListVectorIDs(ctx context.Context, namespace, prefix string, limit int, paginationToken string) ([]string, string, error) {
+	if len(s.Shards) == 0 {
+		return nil, "", errNoShards
+	}
+	shards := s.shardsFor(RouteKey{Namespace: namespace})
+
+	pos, inner := 0, ""
+	if paginationToken != "" {
+		var err error
+		if pos, inner, err = decodeShardToken(paginationToken); err != nil {
+			return nil, "", err
+		}
+		if pos >= len(shards) {
+			return nil, "", errors.New("pinecone: pagination token does not match the shard configuration")
+		}
+	}
+
+	ids, next, err := s.Shards[shards[pos]].ListVectorIDs(ctx, namespace, prefix, limit, inner)
+	if err != nil {
+		return nil, "", err
+	}
+	switch {
+	case next != "":
+		return ids, encodeShardToken(pos, next), nil
+	case pos+1 < len(shards):
+		return ids, encodeShardToken(pos+1, ""), nil
+	default:
+		return ids, "", nil
+	}
+}
+
+// DeleteVectorsByID deletes each ID from its shard, or from every shard when
+// the ID alone does not determine one.
+func (s *ShardedClient) DeleteVectorsByID(ctx context.Context, ids []string, namespace string) error {
+	groups := s.groupIDs(ids, namespace)
+	return s.each(ctx, shardKeys(groups), func(i int, c *Client) error {
+		return c.DeleteVectorsByID(ctx, groups[i], namespace)
+	})
+}
+
+// DeleteAllRecordsInNamespace deletes namespace from every shard relevant to it.
+func (s *ShardedClient) DeleteAllRecordsInNamespace(ctx context.Context, namespace string) error {
+	return s.each(ctx, s.shardsFor(RouteKey{Namespace: namespace}), func(i int, c *Client) error {
+		return c.DeleteAllRecordsInNamespace(ctx, namespace)
+	})
+}
+
+// DeleteVectorsByMetadata deletes the vectors matching filter from every shard
+// relevant to the namespace and filter.
+func (s *ShardedClient) DeleteVectorsByMetadata(ctx context.Context, namespace string, filter map[string]any) error {
+	shards := s.shardsFor(RouteKey{Namespace: namespace, Metadata: filterEqualities(filter)})
+	return s.each(ctx, shards, func(i int, c *Client) error {
+		return c.DeleteVectorsByMetadata(ctx, namespace, filter)
+	})
+}
+
+// DescribeIndexStats sums the statistics of every shard.
+func (s *ShardedClient) DescribeIndexStats(ctx context.Context, filter map[string]any) (*IndexStats, error) {
+	results := make([]*IndexStats, len(s.Shards))
+	err := s.each(ctx, s.shardsFor(RouteKey{}), func(i int, c *Client) error {
+		var err error
+		results[i], err = c.DescribeIndexStats(ctx, filter)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := &IndexStats{Namespaces: make(map[string]NamespaceStats)}
+	for _, st := range results {
+		out.Dimension, out.Metric, out.VectorType = st.Dimension, st.Metric, st.VectorType
+		out.IndexFullness = max(out.IndexFullness, st.IndexFullness)
+		out.TotalVectorCount += st.TotalVectorCount
+		for ns, nsStats := range st.Namespaces {
+			sum := out.Namespaces[ns]
+			sum.VectorCount += nsStats.VectorCount
+			out.Namespaces[ns] = sum
+		}
+	}
+	return out, nil
+}
+
+// shardsFor returns the shards key routes to: one shard, or all of them.
+func (s *ShardedClient) shardsFor(key RouteKey) []int {
+	if i := s.Route(key); i >= 0 && i < len(s.Shards) {
+		return []int{i}
+	}
+	all := make([]int, len(s.Shards))
+	for i := range all {
+		all[i] = i
+	}
+	return all
+}
+
+// groupIDs groups ids by the shards they route to.
+func (s *ShardedClient) groupIDs(ids []string, namespace string) map[int][]string {
+	groups := make(map[int][]string)
+	for _, id := range ids {
+		for _, i := range s.shardsFor(RouteKey{Namespace: namespace, ID: id}) {
+			groups[i] = append(groups[i], id)
+		}
+	}
+	return groups
+}
+
+// each calls fn for every listed shard concurrently, joining their errors.
+func (s *ShardedClient) each(ctx context.Context, shards []int, fn func(i int, c *Client) error) error {
+	limit := s.MaxConcurrency
+	if limit <= 0 {
+		limit = defaultMaxConcurrency
+	}
+
+	errs := make([]error, len(shards))
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for j, i := range shards {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				errs[j] = ctx.Err()
+				return
+			}
+			defer func() { <-sem }()
+
+			if err := fn(i, s.Shards[i]); err != nil {
+				errs[j] = fmt.Errorf("shard %d: %w", i, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// shardKeys returns the shard indexes of groups in ascending order.
+func shardKeys[T any](groups map[int][]T) []int {
+	out := make([]int, 0, len(groups))
+	for i := range groups {
+		out = append(out, i)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// filterEqualities extracts the top-level conditions of filter that pin a
+// field to a single value, written either as {"field": value} or
+// {"field": {"$eq": value}}.
+func filterEqualities(filter map[string]any) map[string]any {
+	var out map[string]any
+	for field, cond := range filter {
+		if strings.HasPrefix(field, "$") {
+			continue
+		}
+		value := cond
+		if ops, ok := cond.(map[string]any); ok {
+			if value, ok = ops["$eq"]; !ok {
+				continue
+			}
+		}
+		if out == nil {
+			out = make(map[string]any)
+		}
+		out[field] = value
+	}
+	return out
+}
+
+// encodeShardToken combines a shard position with that shard's pagination
+// token.
+func encodeShardToken(pos int, inner string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(pos) + ":" + inner))
+}
+
+// decodeShardToken reverses encodeShardToken.
+func decodeShardToken(token string) (int, string, error) {
+	invalid := errors.New("pinecone: invalid sharded pagination token")
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, "", invalid
+	}
+	posStr, inner, ok := strings.Cut(string(b), ":")
+	if !ok {
+		return 0, "", invalid
+	}
+	pos, err := strconv.Atoi(posStr)
+	if err != nil || pos < 0 {
+		return 0, "", invalid
+	}
+	return pos, inner, nil
+}
@@ -0,0 +1,134 @@
+package pinecone
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestRouters(t *testing.T) {
+	prefix := NamespacePrefixRouter(map[string]int{"eu-": 0, "us-": 1, "us-west-": 2})
+	for ns, want := range map[string]int{"eu-acme": 0, "us-acme": 1, "us-west-acme": 2, "apac": AllShards} {
+		if got := prefix(RouteKey{Namespace: ns}); got != want {
+			t.Errorf("prefix router sent %q to %d, want %d", ns, got, want)
+		}
+	}
+
+	meta := MetadataRouter("region", map[string]int{"eu": 0, "us": 1})
+	if got := meta(RouteKey{Metadata: map[string]any{"region": "us"}}); got != 1 {
+		t.Errorf("metadata router returned %d", got)
+	}
+	if got := meta(RouteKey{}); got != AllShards {
+		t.Errorf("expected AllShards without metadata, got %d", got)
+	}
+
+	hash := HashRouter(4)
+	if hash(RouteKey{}) != AllShards {
+		t.Error("expected AllShards without an ID")
+	}
+	moved := 0
+	grown := HashRouter(5)
+	for i := range 1000 {
+		key := RouteKey{ID: "id-" + strconv.Itoa(i)}
+		a, b := hash(key), grown(key)
+		if a < 0 || a >= 4 || b < 0 || b >= 5 {
+			t.Fatalf("hash router out of range: %d, %d", a, b)
+		}
+		if a != b {
+			moved++
+		}
+	}
+	if moved > 300 {
+		t.Errorf("growing from 4 to 5 shards moved %d of 1000 vectors", moved)
+	}
+}
+
+func TestShardedClient(t *testing.T) {
+	ctx := context.Background()
+	eu, euClient := newMemoryIndex(t)
+	us, usClient := newMemoryIndex(t)
+	s := NewShardedClient(MetadataRouter("region", map[string]int{"eu": 0, "us": 1}), euClient, usClient)
+
+	vectors := []*Vector{
+		{ID: "a", Values: []float32{5}, Metadata: map[string]any{"region": "eu"}},
+		{ID: "b", Values: []float32{4}, Metadata: map[string]any{"region": "us"}},
+		{ID: "c", Values: []float32{3}, Metadata: map[string]any{"region": "eu"}},
+		{ID: "d", Values: []float32{2}, Metadata: map[string]any{"region": "us"}},
+		{ID: "e", Values: []float32{1}, Metadata: map[string]any{"region": "us"}},
+	}
+	n, err := s.UpsertVectors(ctx, vectors, "ns")
+	if err != nil || n != 5 {
+		t.Fatalf("upsert returned %d, %v", n, err)
+	}
+	if !slices.Equal(eu.ids("ns"), []string{"a", "c"}) || !slices.Equal(us.ids("ns"), []string{"b", "d", "e"}) {
+		t.Fatalf("unexpected placement: eu=%v us=%v", eu.ids("ns"), us.ids("ns"))
+	}
+
+	if _, err := s.UpsertVectors(ctx, vectorsWithIDs("x"), "ns"); err == nil {
+		t.Error("expected an error upserting a vector that routes to no shard")
+	}
+
+	resp, err := s.QueryByVector(ctx, &QueryByVectorRequest{Vector: []float32{1}, TopK: 3, Namespace: "ns"})
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	var ids []string
+	for _, m := range resp.Matches {
+		ids = append(ids, m.ID)
+	}
+	if !slices.Equal(ids, []string{"a", "b", "c"}) {
+		t.Errorf("expected merged top 3, got %v", ids)
+	}
+
+	resp, err = s.QueryByVector(ctx, &QueryByVectorRequest{
+		Vector: []float32{1}, TopK: 3, Namespace: "ns",
+		Filter: map[string]any{"region": map[string]any{"$eq": "us"}},
+	})
+	if err != nil || len(resp.Matches) != 3 || resp.Matches[0].ID != "b" {
+		t.Errorf("expected the filtered query to reach only the us shard, got %+v, %v", resp, err)
+	}
+
+	fetched, err := s.FetchVectors(ctx, []string{"a", "d", "missing"}, "ns")
+	if err != nil || len(fetched.Vectors) != 2 {
+		t.Errorf("unexpected fetch result: %+v, %v", fetched, err)
+	}
+
+	var listed []string
+	token := ""
+	for pages := 0; ; pages++ {
+		if pages > 10 {
+			t.Fatal("listing did not terminate")
+		}
+		page, next, err := s.ListVectorIDs(ctx, "ns", "", 2, token)
+		if err != nil {
+			t.Fatalf("list failed: %v", err)
+		}
+		listed = append(listed, page...)
+		if next == "" {
+			break
+		}
+		token = next
+	}
+	slices.Sort(listed)
+	if !slices.Equal(listed, []string{"a", "b", "c", "d", "e"}) {
+		t.Errorf("unexpected listing: %v", listed)
+	}
+	if _, _, err := s.ListVectorIDs(ctx, "ns", "", 2, "not-a-token"); err == nil {
+		t.Error("expected an error for an invalid token")
+	}
+
+	if err := s.DeleteVectorsByID(ctx, []string{"a", "b"}, "ns"); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if !slices.Equal(eu.ids("ns"), []string{"c"}) || !slices.Equal(us.ids("ns"), []string{"d", "e"}) {
+		t.Errorf("unexpected contents after delete: eu=%v us=%v", eu.ids("ns"), us.ids("ns"))
+	}
+
+	if err := s.DeleteAllRecordsInNamespace(ctx, "ns"); err != nil {
+		t.Fatalf("delete namespace failed: %v", err)
+	}
+	if len(eu.ids("ns"))+len(us.ids("ns")) != 0 {
+		t.Error("expected the namespace to be deleted from every shard")
+	}
+}
@@ -0,0 +1,50 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// NamespaceStats holds per-namespace statistics.
+type NamespaceStats struct {
+	VectorCount uint32 `json:"vectorCount"`
+}
+
+// IndexStats represents the response from the /describe_index_stats endpoint.
+type IndexStats struct {
+	Namespaces       map[string]NamespaceStats `json:"namespaces"`
+	Dimension        int                       `json:"dimension"`
+	IndexFullness    float64                   `json:"indexFullness"`
+	TotalVectorCount uint32                    `json:"totalVectorCount"`
+	Metric           string                    `json:"metric,omitempty"`
+	VectorType       string                    `json:"vectorType,omitempty"`
+}
+
+// DescribeIndexStats returns statistics about the index's contents, including
+// the vector count per namespace and the index dimension.
+//
+// If filter is non-nil, only vectors matching the metadata filter are counted.
+func (c *Client) DescribeIndexStats(ctx context.Context, filter map[string]any) (*IndexStats, error) {
+	body := map[string]any{}
+	if filter != nil {
+		body["filter"] = filter
+	}
+
+	resp, err := c.do(ctx, http.MethodPost, "/describe_index_stats", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return nil, parseAPIError(resp)
+	}
+
+	var parsed IndexStats
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, err
+	}
+
+	return &parsed, nil
+}
@@ -0,0 +1,43 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDescribeIndexStats(t *testing.T) {
+	t.Run("valid_stats_response", func(t *testing.T) {
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != "/describe_index_stats" {
+				t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+			}
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			if _, ok := body["filter"]; !ok {
+				t.Errorf("expected filter in body, got %v", body)
+			}
+			w.Write([]byte(`{"namespaces":{"ns":{"vectorCount":42}},"dimension":3,"indexFullness":0,"totalVectorCount":42,"metric":"cosine","vectorType":"dense"}`))
+		}))
+		defer s.Close()
+
+		client := &Client{
+			IndexURL:   s.URL,
+			APIKey:     "test-key",
+			HTTPClient: s.Client(),
+		}
+
+		stats, err := client.DescribeIndexStats(context.Background(), map[string]any{"genre": "drama"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if stats.Dimension != 3 || stats.Metric != "cosine" {
+			t.Errorf("unexpected stats: %+v", stats)
+		}
+		if stats.Namespaces["ns"].VectorCount != 42 {
+			t.Errorf("unexpected namespace stats: %+v", stats.Namespaces)
+		}
+	})
+}
@@ -0,0 +1,225 @@
+package pinecone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// VectorStore is the set of vector operations shared by Client, ShardedClient
+// and MirroredClient. Code written against it can swap in fakes for testing or
+// wrap a store with decorators such as ReadOnly, NamespaceScoped and
+// WithLogging.
+type VectorStore interface {
+	UpsertVectors(ctx context.Context, vectors []*Vector, namespace string) (uint32, error)
+	QueryByVector(ctx context.Context, req *QueryByVectorRequest) (*QueryByVectorResponse, error)
+	FetchVectors(ctx context.Context, ids []string, namespace string) (*FetchResponse, error)
+	ListVectorIDs(ctx context.Context, namespace, prefix string, limit int, paginationToken string) ([]string, string, error)
+	DeleteVectorsByID(ctx context.Context, ids []string, namespace string) error
+	DeleteVectorsByMetadata(ctx context.Context, namespace string, filter map[string]any) error
+	DeleteAllRecordsInNamespace(ctx context.Context, namespace string) error
+}
+
+var (
+	_ VectorStore = (*Client)(nil)
+	_ VectorStore = (*ShardedClient)(nil)
+	_ VectorStore = (*MirroredClient)(nil)
+)
+
+// ErrReadOnly is returned by writes through a store wrapped with ReadOnly.
+var ErrReadOnly = errors.New("pinecone: store is read-only")
+
+// ErrNamespaceNotAllowed is returned by calls through a store wrapped with
+// NamespaceScoped that name a namespace outside its scope.
+var ErrNamespaceNotAllowed = errors.New("pinecone: namespace not allowed")
+
+// ReadOnly returns a store that passes reads to s and fails every write with
+// ErrReadOnly.
+func ReadOnly(s VectorStore) VectorStore {
+	return readOnlyStore{s: s}
+}
+
+// readOnlyStore implements every method explicitly rather than embedding s,
+// so a write method added to VectorStore cannot reach s unnoticed.
+type readOnlyStore struct {
+	s VectorStore
+}
+
+func (r readOnlyStore) QueryByVector(ctx context.Context, req *QueryByVectorRequest) (*QueryByVectorResponse, error) {
+	return r.s.QueryByVector(ctx, req)
+}
+
+func (r readOnlyStore) FetchVectors(ctx context.Context, ids []string, namespace string) (*FetchResponse, error) {
+	return r.s.FetchVectors(ctx, ids, namespace)
+}
+
+func (r readOnlyStore) ListVectorIDs(ctx context.Context, namespace, prefix string, limit int, paginationToken string) ([]string, string, error) {
+	return r.s.ListVectorIDs(ctx, namespace, prefix, limit, paginationToken)
+}
+
+func (readOnlyStore) UpsertVectors(context.Context, []*Vector, string) (uint32, error) {
+	return 0, ErrReadOnly
+}
+
+func (readOnlyStore) DeleteVectorsByID(context.Context, []string, string) error {
+	return ErrReadOnly
+}
+
+func (readOnlyStore) DeleteVectorsByMetadata(context.Context, string, map[string]any) error {
+	return ErrReadOnly
+}
+
+func (readOnlyStore) DeleteAllRecordsInNamespace(context.Context, string) error {
+	return ErrReadOnly
+}
+
+// NamespaceScoped returns a store that passes calls to s only for the given
+// namespaces, failing any other with ErrNamespaceNotAllowed.
+func NamespaceScoped(s VectorStore, namespaces ...string) VectorStore {
+	return &namespaceStore{s: s, allowed: slices.Clone(namespaces)}
+}
+
+type namespaceStore struct {
+	s       VectorStore
+	allowed []string
+}
+
+func (n *namespaceStore) check(namespace string) error {
+	if !slices.Contains(n.allowed, namespace) {
+		return fmt.Errorf("%w: %q", ErrNamespaceNotAllowed, namespace)
+	}
+	return nil
+}
+
+func (n *namespaceStore) UpsertVectors(ctx context.Context, vectors []*Vector, namespace string) (uint32, error) {
+	if err := n.check(namespace); err != nil {
+		return 0, err
+	}
+	return n.s.UpsertVectors(ctx, vectors, namespace)
+}
+
+func (n *namespaceStore) QueryByVector(ctx context.Context, req *QueryByVectorRequest) (*QueryByVectorResponse, error) {
+	if err := n.check(req.Namespace); err != nil {
+		return nil, err
+	}
+	return n.s.QueryByVector(ctx, req)
+}
+
+func (n *namespaceStore) FetchVectors(ctx context.Context, ids []string, namespace string) (*FetchResponse, error) {
+	if err := n.check(namespace); err != nil {
+		return nil, err
+	}
+	return n.s.FetchVectors(ctx, ids, namespace)
+}
+
+func (n *namespaceStore) ListVectorIDs(ctx context.Context, namespace, prefix string, limit int, paginationToken string) ([]string, string, error) {
+	if err := n.check(namespace); err != nil {
+		return nil, "", err
+	}
+	return n.s.ListVectorIDs(ctx, namespace, prefix, limit, paginationToken)
+}
+
+func (n *namespaceStore) DeleteVectorsByID(ctx context.Context, ids []string, namespace string) error {
+	if err := n.check(namespace); err != nil {
+		return err
+	}
+	return n.s.DeleteVectorsByID(ctx, ids, namespace)
+}
+
+func (n *namespaceStore) DeleteVectorsByMetadata(ctx context.Context, namespace string, filter map[string]any) error {
+	if err := n.check(namespace); err != nil {
+		return err
+	}
+	return n.s.DeleteVectorsByMetadata(ctx, namespace, filter)
+}
+
+func (n *namespaceStore) DeleteAllRecordsInNamespace(ctx context.Context, namespace string) error {
+	if err := n.check(namespace); err != nil {
+		return err
+	}
+	return n.s.DeleteAllRecordsInNamespace(ctx, namespace)
+}
+
+// WithLogging returns a store that logs every call to s with its operation,
+// namespace, size and duration: successes at debug level and failures at
+// error level. A nil logger uses slog.Default.
+func WithLogging(s VectorStore, logger *slog.Logger) VectorStore {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &loggingStore{s: s, logger: logger}
+}
+
+type loggingStore struct {
+	s      VectorStore
+	logger *slog.Logger
+}
+
+func (l *loggingStore) log(ctx context.Context, op, namespace string, start time.Time, err error, attrs ...slog.Attr) {
+	attrs = append(attrs,
+		slog.String("op", op),
+		slog.String("namespace", namespace),
+		slog.Duration("duration", time.Since(start)),
+	)
+	if err != nil {
+		l.logger.LogAttrs(ctx, slog.LevelError, "pinecone request failed", append(attrs, slog.Any("error", err))...)
+		return
+	}
+	l.logger.LogAttrs(ctx, slog.LevelDebug, "pinecone request", attrs...)
+}
+
+func (l *loggingStore) UpsertVectors(ctx context.Context, vectors []*Vector, namespace string) (uint32, error) {
+	start := time.Now()
+	n, err := l.s.UpsertVectors(ctx, vectors, namespace)
+	l.log(ctx, "UpsertVectors", namespace, start, err, slog.Int("vectors", len(vectors)))
+	return n, err
+}
+
+func (l *loggingStore) QueryByVector(ctx context.Context, req *QueryByVectorRequest) (*QueryByVectorResponse, error) {
+	start := time.Now()
+	resp, err := l.s.QueryByVector(ctx, req)
+	attrs := []slog.Attr{slog.Int("top_k", req.TopK)}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("matches", len(resp.Matches)))
+	}
+	l.log(ctx, "QueryByVector", req.Namespace, start, err, attrs...)
+	return resp, err
+}
+
+func (l *loggingStore) FetchVectors(ctx context.Context, ids []string, namespace string) (*FetchResponse, error) {
+	start := time.Now()
+	resp, err := l.s.FetchVectors(ctx, ids, namespace)
+	l.log(ctx, "FetchVectors", namespace, start, err, slog.Int("ids", len(ids)))
+	return resp, err
+}
+
+func (l *loggingStore) ListVectorIDs(ctx context.Context, namespace, prefix string, limit int, paginationToken string) ([]string, string, error) {
+	start := time.Now()
+	ids, next, err := l.s.ListVectorIDs(ctx, namespace, prefix, limit, paginationToken)
+	l.log(ctx, "ListVectorIDs", namespace, start, err, slog.Int("ids", len(ids)))
+	return ids, next, err
+}
+
+func (l *loggingStore) DeleteVectorsByID(ctx context.Context, ids []string, namespace string) error {
+	start := time.Now()
+	err := l.s.DeleteVectorsByID(ctx, ids, namespace)
+	l.log(ctx, "DeleteVectorsByID", namespace, start, err, slog.Int("ids", len(ids)))
+	return err
+}
+
+func (l *loggingStore) DeleteVectorsByMetadata(ctx context.Context, namespace string, filter map[string]any) error {
+	start := time.Now()
+	err := l.s.DeleteVectorsByMetadata(ctx, namespace, filter)
+	l.log(ctx, "DeleteVectorsByMetadata", namespace, start, err)
+	return err
+}
+
+func (l *loggingStore) DeleteAllRecordsInNamespace(ctx context.Context, namespace string) error {
+	start := time.Now()
+	err := l.s.DeleteAllRecordsInNamespace(ctx, namespace)
+	l.log(ctx, "DeleteAllRecordsInNamespace", namespace, start, err)
+	return err
+}
@@ -0,0 +1,77 @@
+package pinecone
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestReadOnly(t *testing.T) {
+	ctx := context.Background()
+	m, c := newMemoryIndex(t)
+	m.put("ns", vectorsWithIDs("a")...)
+	s := ReadOnly(c)
+
+	if _, err := s.UpsertVectors(ctx, vectorsWithIDs("b"), "ns"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly from upsert, got %v", err)
+	}
+	if err := s.DeleteVectorsByID(ctx, []string{"a"}, "ns"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly from delete, got %v", err)
+	}
+	if err := s.DeleteAllRecordsInNamespace(ctx, "ns"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly from namespace delete, got %v", err)
+	}
+
+	resp, err := s.FetchVectors(ctx, []string{"a"}, "ns")
+	if err != nil || len(resp.Vectors) != 1 {
+		t.Errorf("expected reads to pass through, got %+v, %v", resp, err)
+	}
+	if len(m.ids("ns")) != 1 {
+		t.Error("read-only store modified the index")
+	}
+}
+
+func TestNamespaceScoped(t *testing.T) {
+	ctx := context.Background()
+	m, c := newMemoryIndex(t)
+	s := NamespaceScoped(c, "tenant-a")
+
+	if _, err := s.UpsertVectors(ctx, vectorsWithIDs("a"), "tenant-a"); err != nil {
+		t.Fatalf("upsert to the allowed namespace failed: %v", err)
+	}
+	if _, err := s.UpsertVectors(ctx, vectorsWithIDs("a"), "tenant-b"); !errors.Is(err, ErrNamespaceNotAllowed) {
+		t.Errorf("expected ErrNamespaceNotAllowed, got %v", err)
+	}
+	if _, err := s.QueryByVector(ctx, &QueryByVectorRequest{Vector: []float32{1}, TopK: 1, Namespace: "tenant-b"}); !errors.Is(err, ErrNamespaceNotAllowed) {
+		t.Errorf("expected queries to be scoped too, got %v", err)
+	}
+	if len(m.ids("tenant-b")) != 0 {
+		t.Error("scoped store wrote outside its namespace")
+	}
+}
+
+func TestWithLogging(t *testing.T) {
+	ctx := context.Background()
+	_, c := newMemoryIndex(t)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	s := WithLogging(ReadOnly(c), logger)
+
+	s.FetchVectors(ctx, []string{"a", "b"}, "ns")
+	s.DeleteVectorsByID(ctx, []string{"a"}, "ns")
+
+	out := buf.String()
+	for _, want := range []string{
+		"level=DEBUG msg=\"pinecone request\" ids=2 op=FetchVectors namespace=ns",
+		"level=ERROR msg=\"pinecone request failed\" ids=1 op=DeleteVectorsByID namespace=ns",
+		"error=\"pinecone: store is read-only\"",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log output missing %q:\n%s", want, out)
+		}
+	}
+}
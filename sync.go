@@ -0,0 +1,282 @@
+package pinecone
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"math"
+	"os"
+)
+
+// DefaultHashField is the metadata field SyncNamespace stores content hashes
+// in when SyncOptions.HashField is not set.
+const DefaultHashField = "_content_hash"
+
+// Batch limits used by SyncNamespace and the other bulk helpers.
+const (
+	defaultSyncBatchSize = 100
+	maxFetchIDs          = 100
+	maxDeleteIDs         = 1000
+)
+
+// SyncOptions configures SyncNamespace.
+type SyncOptions struct {
+	// HashField is the reserved metadata field holding each vector's content
+	// hash. Defaults to DefaultHashField. Unused when ManifestPath is set.
+	HashField string
+
+	// ManifestPath, if set, keeps content hashes in this local JSON file
+	// instead of in vector metadata, avoiding a fetch of every vector. The
+	// manifest is only as accurate as the writes made through it: vectors
+	// changed or deleted by other means are not detected.
+	ManifestPath string
+
+	// DeleteMissing deletes vectors that exist in the namespace but are absent
+	// from the source set.
+	DeleteMissing bool
+
+	// DryRun reports what would change without upserting, deleting or
+	// updating the manifest.
+	DryRun bool
+
+	// BatchSize is the number of vectors sent per upsert. Defaults to 100.
+	BatchSize int
+}
+
+// SyncReport describes the outcome of SyncNamespace.
+type SyncReport struct {
+	// Upserted holds the IDs of new or changed vectors.
+	Upserted []string
+
+	// Deleted holds the IDs removed because they were absent from the source.
+	Deleted []string
+
+	// Unchanged counts vectors skipped because their hash matched.
+	Unchanged int
+}
+
+// syncManifest is the on-disk form of SyncOptions.ManifestPath, mapping
+// namespace to vector ID to content hash.
+type syncManifest map[string]map[string]string
+
+// SyncNamespace makes namespace hold vectors, upserting only the vectors whose
+// values or metadata changed since the last sync. Unchanged vectors are
+// detected by a SHA-256 hash of their contents, stored either in a reserved
+// metadata field or in a local manifest file.
+//
+// On error the returned report describes the work completed so far.
+func (c *Client) SyncNamespace(ctx context.Context, namespace string, vectors []*Vector, opts *SyncOptions) (*SyncReport, error) {
+	if opts == nil {
+		opts = &SyncOptions{}
+	}
+	hashField := opts.HashField
+	if hashField == "" {
+		hashField = DefaultHashField
+	}
+	if opts.ManifestPath != "" {
+		hashField = ""
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultSyncBatchSize
+	}
+
+	var manifest syncManifest
+	var previous map[string]string
+	var err error
+	if opts.ManifestPath != "" {
+		if manifest, err = loadSyncManifest(opts.ManifestPath); err != nil {
+			return nil, err
+		}
+		previous = manifest[namespace]
+	} else if previous, err = c.fetchHashes(ctx, namespace, vectors, hashField); err != nil {
+		return nil, err
+	}
+
+	report := &SyncReport{}
+	current := make(map[string]string, len(vectors))
+	var changed []*Vector
+	for _, v := range vectors {
+		h, err := contentHash(v, hashField)
+		if err != nil {
+			return nil, err
+		}
+		current[v.ID] = h
+		if previous[v.ID] == h {
+			report.Unchanged++
+			continue
+		}
+		if hashField != "" {
+			v = withMetadataField(v, hashField, h)
+		}
+		changed = append(changed, v)
+	}
+
+	for start := 0; start < len(changed); start += batchSize {
+		batch := changed[start:min(start+batchSize, len(changed))]
+		if !opts.DryRun {
+			if _, err := c.UpsertVectors(ctx, batch, namespace); err != nil {
+				return report, err
+			}
+		}
+		for _, v := range batch {
+			report.Upserted = append(report.Upserted, v.ID)
+		}
+	}
+
+	if opts.DeleteMissing {
+		ids, err := c.listAllIDs(ctx, namespace, "")
+		if err != nil {
+			return report, err
+		}
+		var missing []string
+		for _, id := range ids {
+			if _, ok := current[id]; !ok {
+				missing = append(missing, id)
+			}
+		}
+		for start := 0; start < len(missing); start += maxDeleteIDs {
+			batch := missing[start:min(start+maxDeleteIDs, len(missing))]
+			if !opts.DryRun {
+				if err := c.DeleteVectorsByID(ctx, batch, namespace); err != nil {
+					return report, err
+				}
+			}
+			report.Deleted = append(report.Deleted, batch...)
+		}
+	}
+
+	if manifest != nil && !opts.DryRun {
+		if !opts.DeleteMissing {
+			for id, h := range previous {
+				if _, ok := current[id]; !ok {
+					current[id] = h
+				}
+			}
+		}
+		manifest[namespace] = current
+		if err := saveSyncManifest(opts.ManifestPath, manifest); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// fetchHashes returns the stored content hashes of the vectors that already
+// exist in namespace.
+func (c *Client) fetchHashes(ctx context.Context, namespace string, vectors []*Vector, hashField string) (map[string]string, error) {
+	hashes := make(map[string]string, len(vectors))
+	ids := make([]string, 0, maxFetchIDs)
+	for i, v := range vectors {
+		ids = append(ids, v.ID)
+		if len(ids) < maxFetchIDs && i < len(vectors)-1 {
+			continue
+		}
+
+		resp, err := c.FetchVectors(ctx, ids, namespace)
+		if err != nil {
+			return nil, err
+		}
+		for id, fv := range resp.Vectors {
+			if h, ok := fv.Metadata[hashField].(string); ok {
+				hashes[id] = h
+			}
+		}
+		ids = ids[:0]
+	}
+	return hashes, nil
+}
+
+// listAllIDs pages through every vector ID in namespace that starts with prefix.
+func (c *Client) listAllIDs(ctx context.Context, namespace, prefix string) ([]string, error) {
+	var all []string
+	token := ""
+	for {
+		ids, next, err := c.ListVectorIDs(ctx, namespace, prefix, 0, token)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, ids...)
+		if next == "" {
+			return all, nil
+		}
+		token = next
+	}
+}
+
+// contentHash hashes v's dense values, sparse values and metadata, ignoring
+// the metadata field hashField.
+func contentHash(v *Vector, hashField string) (string, error) {
+	h := sha256.New()
+	var b [4]byte
+
+	binary.LittleEndian.PutUint32(b[:], uint32(len(v.Values)))
+	h.Write(b[:])
+	for _, f := range v.Values {
+		binary.LittleEndian.PutUint32(b[:], math.Float32bits(f))
+		h.Write(b[:])
+	}
+
+	if sv := v.SparseValues; sv != nil {
+		binary.LittleEndian.PutUint32(b[:], uint32(len(sv.Indices)))
+		h.Write(b[:])
+		for i, idx := range sv.Indices {
+			binary.LittleEndian.PutUint32(b[:], idx)
+			h.Write(b[:])
+			if i < len(sv.Values) {
+				binary.LittleEndian.PutUint32(b[:], math.Float32bits(sv.Values[i]))
+				h.Write(b[:])
+			}
+		}
+	}
+
+	md, err := canonicalMetadata(v.Metadata, []string{hashField})
+	if err != nil {
+		return "", err
+	}
+	h.Write(md)
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// withMetadataField returns a copy of v with metadata field key set to value.
+func withMetadataField(v *Vector, key string, value any) *Vector {
+	out := *v
+	out.Metadata = make(map[string]any, len(v.Metadata)+1)
+	for k, val := range v.Metadata {
+		out.Metadata[k] = val
+	}
+	out.Metadata[key] = value
+	return &out
+}
+
+// loadSyncManifest reads the manifest at path, returning an empty manifest if
+// the file does not exist.
+func loadSyncManifest(path string) (syncManifest, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return syncManifest{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m := syncManifest{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// saveSyncManifest atomically writes m to path.
+func saveSyncManifest(path string, m syncManifest) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
@@ -0,0 +1,258 @@
+package pinecone
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// memoryIndex is an in-memory stand-in for a Pinecone index, serving upsert,
+// query, fetch, list and delete requests. Listing pages through IDs in sorted order,
+// pageSize at a time unless the request sets a limit.
+type memoryIndex struct {
+	mu         sync.Mutex
+	namespaces map[string]map[string]*Vector
+	pageSize   int
+	upserts    int
+
+	// filters records the filter of every query and delete, in order.
+	filters []map[string]any
+}
+
+func newMemoryIndex(t *testing.T) (*memoryIndex, *Client) {
+	m := &memoryIndex{namespaces: map[string]map[string]*Vector{}, pageSize: 2}
+	s := httptest.NewServer(m)
+	t.Cleanup(s.Close)
+	return m, NewClient(s.URL, "key")
+}
+
+// put stores vectors directly, bypassing the HTTP API.
+func (m *memoryIndex) put(namespace string, vectors ...*Vector) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ns := m.namespaces[namespace]
+	if ns == nil {
+		ns = map[string]*Vector{}
+		m.namespaces[namespace] = ns
+	}
+	for _, v := range vectors {
+		ns[v.ID] = v
+	}
+}
+
+// ids returns the sorted IDs stored in namespace.
+func (m *memoryIndex) ids(namespace string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var ids []string
+	for id := range m.namespaces[namespace] {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func (m *memoryIndex) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Vectors   []*Vector      `json:"vectors"`
+		IDs       []string       `json:"ids"`
+		Namespace string         `json:"namespace"`
+		Vector    []float32      `json:"vector"`
+		TopK      int            `json:"topK"`
+		Filter    map[string]any `json:"filter"`
+	}
+	if r.ContentLength > 0 {
+		json.NewDecoder(r.Body).Decode(&body)
+	}
+	if r.URL.Path == "/query" || r.URL.Path == "/vectors/delete" {
+		m.mu.Lock()
+		m.filters = append(m.filters, body.Filter)
+		m.mu.Unlock()
+	}
+
+	switch r.URL.Path {
+	case "/vectors/upsert":
+		m.put(body.Namespace, body.Vectors...)
+		m.mu.Lock()
+		m.upserts += len(body.Vectors)
+		m.mu.Unlock()
+		json.NewEncoder(w).Encode(UpsertResponse{UpsertedCount: uint32(len(body.Vectors))})
+
+	case "/vectors/fetch":
+		q := r.URL.Query()
+		resp := FetchResponse{Vectors: map[string]*Vector{}, Namespace: q.Get("namespace")}
+		m.mu.Lock()
+		for _, id := range q["ids"] {
+			if v, ok := m.namespaces[resp.Namespace][id]; ok {
+				resp.Vectors[id] = v
+			}
+		}
+		m.mu.Unlock()
+		json.NewEncoder(w).Encode(resp)
+
+	case "/vectors/list":
+		q := r.URL.Query()
+		var page []map[string]string
+		pageSize := m.pageSize
+		if n, _ := strconv.Atoi(q.Get("limit")); n > 0 {
+			pageSize = n
+		}
+		start, _ := strconv.Atoi(q.Get("paginationToken"))
+		ids := m.ids(q.Get("namespace"))
+		for _, id := range ids[min(start, len(ids)):] {
+			if len(page) == pageSize {
+				break
+			}
+			if strings.HasPrefix(id, q.Get("prefix")) {
+				page = append(page, map[string]string{"id": id})
+			}
+			start++
+		}
+		next := ""
+		if start < len(ids) {
+			next = strconv.Itoa(start)
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"vectors":    page,
+			"pagination": map[string]string{"next": next},
+		})
+
+	case "/query":
+		// Score by dot product, best first.
+		var matches []MatchResult
+		m.mu.Lock()
+		for _, v := range m.namespaces[body.Namespace] {
+			score := 0.0
+			for i := range min(len(v.Values), len(body.Vector)) {
+				score += float64(v.Values[i]) * float64(body.Vector[i])
+			}
+			matches = append(matches, MatchResult{ID: v.ID, Score: score, Metadata: v.Metadata})
+		}
+		m.mu.Unlock()
+		slices.SortFunc(matches, func(a, b MatchResult) int {
+			if a.Score != b.Score {
+				return cmp.Compare(b.Score, a.Score)
+			}
+			return strings.Compare(a.ID, b.ID)
+		})
+		json.NewEncoder(w).Encode(QueryByVectorResponse{Matches: matches[:min(body.TopK, len(matches))], Namespace: body.Namespace})
+
+	case "/vectors/delete":
+		m.mu.Lock()
+		for _, id := range body.IDs {
+			delete(m.namespaces[body.Namespace], id)
+		}
+		m.mu.Unlock()
+
+	default:
+		if ns, ok := strings.CutPrefix(r.URL.Path, "/namespaces/"); ok && r.Method == http.MethodDelete {
+			m.mu.Lock()
+			delete(m.namespaces, ns)
+			m.mu.Unlock()
+			return
+		}
+		http.NotFound(w, r)
+	}
+}
+
+func TestSyncNamespace(t *testing.T) {
+	ctx := context.Background()
+	source := func() []*Vector {
+		return []*Vector{
+			{ID: "a", Values: []float32{1, 2}, Metadata: map[string]any{"k": "v"}},
+			{ID: "b", Values: []float32{3, 4}},
+		}
+	}
+
+	t.Run("metadata_hashes", func(t *testing.T) {
+		m, c := newMemoryIndex(t)
+		m.put("ns", &Vector{ID: "stale", Values: []float32{0, 0}})
+
+		report, err := c.SyncNamespace(ctx, "ns", source(), nil)
+		if err != nil {
+			t.Fatalf("first sync failed: %v", err)
+		}
+		if len(report.Upserted) != 2 || report.Unchanged != 0 || len(report.Deleted) != 0 {
+			t.Errorf("unexpected first report: %+v", report)
+		}
+		if _, ok := m.namespaces["ns"]["a"].Metadata[DefaultHashField].(string); !ok {
+			t.Error("expected the content hash to be stored in metadata")
+		}
+
+		vectors := source()
+		vectors[1].Values[0] = 30
+		report, err = c.SyncNamespace(ctx, "ns", vectors, &SyncOptions{DeleteMissing: true})
+		if err != nil {
+			t.Fatalf("second sync failed: %v", err)
+		}
+		if !slices.Equal(report.Upserted, []string{"b"}) || report.Unchanged != 1 {
+			t.Errorf("expected only b to be upserted, got %+v", report)
+		}
+		if !slices.Equal(report.Deleted, []string{"stale"}) {
+			t.Errorf("expected stale to be deleted, got %v", report.Deleted)
+		}
+		if got := m.ids("ns"); !slices.Equal(got, []string{"a", "b"}) {
+			t.Errorf("unexpected namespace contents: %v", got)
+		}
+	})
+
+	t.Run("manifest", func(t *testing.T) {
+		m, c := newMemoryIndex(t)
+		opts := &SyncOptions{ManifestPath: filepath.Join(t.TempDir(), "manifest.json")}
+
+		if _, err := c.SyncNamespace(ctx, "ns", source(), opts); err != nil {
+			t.Fatalf("first sync failed: %v", err)
+		}
+		if _, ok := m.namespaces["ns"]["a"].Metadata[DefaultHashField]; ok {
+			t.Error("manifest mode should not write the hash into metadata")
+		}
+
+		report, err := c.SyncNamespace(ctx, "ns", source(), opts)
+		if err != nil {
+			t.Fatalf("second sync failed: %v", err)
+		}
+		if len(report.Upserted) != 0 || report.Unchanged != 2 || m.upserts != 2 {
+			t.Errorf("expected nothing to be resent, got %+v after %d upserts", report, m.upserts)
+		}
+	})
+
+	t.Run("dry_run", func(t *testing.T) {
+		m, c := newMemoryIndex(t)
+		m.put("ns", &Vector{ID: "stale", Values: []float32{0, 0}})
+
+		report, err := c.SyncNamespace(ctx, "ns", source(), &SyncOptions{DeleteMissing: true, DryRun: true})
+		if err != nil {
+			t.Fatalf("sync failed: %v", err)
+		}
+		if len(report.Upserted) != 2 || !slices.Equal(report.Deleted, []string{"stale"}) {
+			t.Errorf("unexpected report: %+v", report)
+		}
+		if got := m.ids("ns"); !slices.Equal(got, []string{"stale"}) {
+			t.Errorf("dry run modified the namespace: %v", got)
+		}
+	})
+}
+
+func TestContentHash(t *testing.T) {
+	a := &Vector{ID: "a", Values: []float32{1, 2}, Metadata: map[string]any{"x": 1, "y": "z"}}
+	b := &Vector{ID: "b", Values: []float32{1, 2}, Metadata: map[string]any{"y": "z", "x": 1.0, DefaultHashField: "old"}}
+
+	ha, _ := contentHash(a, DefaultHashField)
+	hb, _ := contentHash(b, DefaultHashField)
+	if ha != hb {
+		t.Error("expected equal contents to hash equally, ignoring the hash field")
+	}
+
+	b.Values[1] = 3
+	if hb, _ = contentHash(b, DefaultHashField); ha == hb {
+		t.Error("expected changed values to change the hash")
+	}
+}
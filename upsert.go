@@ -4,13 +4,65 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"slices"
 )
 
-// Vector represents a single dense vector with optional metadata.
+// Vector represents a single dense vector with optional sparse values and metadata.
+//
+// Values are float32, matching Pinecone's storage precision. Use NewVector or
+// Float32s to build vectors from embeddings held as float64. Vectors for a sparse index leave Values
+// empty and set only SparseValues.
 type Vector struct {
-	ID       string         `json:"id"`
-	Values   []float64      `json:"values"`
-	Metadata map[string]any `json:"metadata,omitempty"`
+	ID           string         `json:"id"`
+	Values       []float32      `json:"values,omitempty"`
+	SparseValues *SparseValues  `json:"sparseValues,omitempty"`
+	Metadata     map[string]any `json:"metadata,omitempty"`
+}
+
+// SparseValues represents a sparse vector as parallel slices of dimension
+// indices and their values.
+type SparseValues struct {
+	Indices []uint32  `json:"indices"`
+	Values  []float32 `json:"values"`
+}
+
+// Float32s converts a slice of any float type to the []float32 used by Vector
+// and QueryByVectorRequest.
+func Float32s[T ~float32 | ~float64](values []T) []float32 {
+	if values == nil {
+		return nil
+	}
+	out := make([]float32, len(values))
+	for i, x := range values {
+		out[i] = float32(x)
+	}
+	return out
+}
+
+// NewVector returns a vector with the given values, converting them to float32
+// if they are float64.
+func NewVector[T ~float32 | ~float64](id string, values []T, metadata map[string]any) *Vector {
+	return &Vector{ID: id, Values: Float32s(values), Metadata: metadata}
+}
+
+// cloneVector deep-copies v, including its metadata.
+func cloneVector(v *Vector) *Vector {
+	if v == nil {
+		return nil
+	}
+	out := &Vector{ID: v.ID, Values: slices.Clone(v.Values), SparseValues: v.SparseValues.clone()}
+	if v.Metadata != nil {
+		out.Metadata = cloneValue(v.Metadata).(map[string]any)
+	}
+	return out
+}
+
+// clone deep-copies s, returning nil if s is nil.
+func (s *SparseValues) clone() *SparseValues {
+	if s == nil {
+		return nil
+	}
+	return &SparseValues{Indices: slices.Clone(s.Indices), Values: slices.Clone(s.Values)}
 }
 
 // UpsertRequest is the payload structure for upserting vectors.
@@ -26,13 +78,29 @@ type UpsertResponse struct {
 
 // UpsertVectors inserts or updates one or more vectors into the specified namespace.
 // Returns the number of vectors upserted or an error.
+//
+// If c.Validator is set, every vector is checked before the request is sent and
+// a *ValidationError is returned for the first invalid one. If
+// c.MetadataValidator is set, metadata is checked, and shrunk where configured,
+// in the same way.
 func (c *Client) UpsertVectors(ctx context.Context, vectors []*Vector, namespace string) (uint32, error) {
+	if err := c.validateVectors(vectors); err != nil {
+		return 0, err
+	}
+	if c.MetadataValidator != nil {
+		var err error
+		if vectors, err = c.MetadataValidator.Check(vectors); err != nil {
+			return 0, err
+		}
+	}
+
 	payload := UpsertRequest{
 		Vectors:   vectors,
 		Namespace: namespace,
 	}
 
-	resp, err := c.do(ctx, http.MethodPost, "/vectors/upsert", payload)
+	defer c.invalidate(namespace)
+	resp, err := c.do(ctx, http.MethodPost, "/vectors/upsert", &payload)
 	if err != nil {
 		return 0, err
 	}
@@ -2,6 +2,7 @@ package pinecone
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -30,7 +31,7 @@ func TestUpsertVectors(t *testing.T) {
 		}
 
 		_, err := client.UpsertVectors(context.Background(), []*Vector{
-			{ID: "vec1", Values: []float64{0.1}},
+			{ID: "vec1", Values: []float32{0.1}},
 		}, "ns")
 		if err == nil {
 			t.Fatal("expected error on HTTP request failure")
@@ -50,7 +51,7 @@ func TestUpsertVectors(t *testing.T) {
 		}
 
 		_, err := client.UpsertVectors(context.Background(), []*Vector{
-			{ID: "v1", Values: []float64{0.1}},
+			{ID: "v1", Values: []float32{0.1}},
 		}, "ns")
 
 		if err == nil || !strings.Contains(err.Error(), "invalid character") {
@@ -58,3 +59,40 @@ func TestUpsertVectors(t *testing.T) {
 		}
 	})
 }
+
+func TestFloat32s(t *testing.T) {
+	t.Run("converts_float64", func(t *testing.T) {
+		got := Float32s([]float64{0.1, 0.2})
+		if len(got) != 2 || got[0] != float32(0.1) || got[1] != float32(0.2) {
+			t.Errorf("unexpected values: %v", got)
+		}
+	})
+
+	t.Run("nil_stays_nil", func(t *testing.T) {
+		if got := Float32s[float64](nil); got != nil {
+			t.Errorf("expected nil, got %v", got)
+		}
+	})
+
+	t.Run("compact_json_encoding", func(t *testing.T) {
+		b, err := json.Marshal(&Vector{ID: "v", Values: Float32s([]float64{0.1, 0.3})})
+		if err != nil {
+			t.Fatalf("marshal failed: %v", err)
+		}
+		if string(b) != `{"id":"v","values":[0.1,0.3]}` {
+			t.Errorf("unexpected encoding: %s", b)
+		}
+	})
+
+	t.Run("constructors_accept_float64", func(t *testing.T) {
+		embedding := []float64{0.5, 0.25}
+		v := NewVector("v", embedding, map[string]any{"k": "v"})
+		if v.ID != "v" || v.Values[1] != 0.25 || v.Metadata["k"] != "v" {
+			t.Errorf("unexpected vector: %+v", v)
+		}
+		req := NewQueryByVectorRequest(embedding, 3, "ns")
+		if req.Vector[0] != 0.5 || req.TopK != 3 || req.Namespace != "ns" {
+			t.Errorf("unexpected request: %+v", req)
+		}
+	})
+}
@@ -0,0 +1,175 @@
+package pinecone
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+)
+
+// Metric is the distance metric an index uses to score similarity.
+type Metric string
+
+const (
+	MetricCosine     Metric = "cosine"
+	MetricEuclidean  Metric = "euclidean"
+	MetricDotProduct Metric = "dotproduct"
+)
+
+// better reports whether score a ranks ahead of score b. Euclidean scores are
+// distances, so lower is better; for the other metrics higher is better.
+func (m Metric) better(a, b float64) bool {
+	if m == MetricEuclidean {
+		return a < b
+	}
+	return a > b
+}
+
+// VectorValidator checks vectors against an index's dimension and metric on the
+// client, before a request is sent.
+type VectorValidator struct {
+	// Dimension is the index dimension. Zero skips the length check.
+	Dimension int
+
+	// Metric is the index metric. Zero vectors are rejected for MetricCosine,
+	// where similarity is undefined.
+	Metric Metric
+}
+
+// ValidationError describes a vector rejected by a VectorValidator.
+type ValidationError struct {
+	// Index identifies the index the vector was checked against.
+	Index string
+
+	// VectorID is the ID of the offending vector, or empty for a query vector.
+	VectorID string
+
+	Reason string
+}
+
+// Error returns the string representation of the validation error.
+func (e *ValidationError) Error() string {
+	subject := "query vector"
+	if e.VectorID != "" {
+		subject = fmt.Sprintf("vector %q", e.VectorID)
+	}
+	if e.Index == "" {
+		return fmt.Sprintf("pinecone: invalid %s: %s", subject, e.Reason)
+	}
+	return fmt.Sprintf("pinecone: invalid %s for index %s: %s", subject, e.Index, e.Reason)
+}
+
+// ValidateVector checks a vector to be upserted. It returns a *ValidationError
+// describing the first problem found, or nil.
+func (v *VectorValidator) ValidateVector(vec *Vector) error {
+	if vec == nil {
+		return &ValidationError{Reason: "nil vector"}
+	}
+	if vec.ID == "" {
+		return &ValidationError{Reason: "empty id"}
+	}
+	if vec.SparseValues != nil {
+		if reason := checkSparse(vec.SparseValues); reason != "" {
+			return &ValidationError{VectorID: vec.ID, Reason: reason}
+		}
+		if len(vec.Values) == 0 {
+			return nil
+		}
+	}
+	if reason := v.check(vec.Values); reason != "" {
+		return &ValidationError{VectorID: vec.ID, Reason: reason}
+	}
+	return nil
+}
+
+// ValidateQuery checks a query vector. It returns a *ValidationError
+// describing the first problem found, or nil.
+func (v *VectorValidator) ValidateQuery(values []float32) error {
+	if reason := v.check(values); reason != "" {
+		return &ValidationError{Reason: reason}
+	}
+	return nil
+}
+
+// check returns the reason values are invalid, or an empty string.
+func (v *VectorValidator) check(values []float32) string {
+	if v.Dimension > 0 && len(values) != v.Dimension {
+		return fmt.Sprintf("dimension %d does not match index dimension %d", len(values), v.Dimension)
+	}
+
+	zero := true
+	for i, x := range values {
+		if math.IsNaN(float64(x)) || math.IsInf(float64(x), 0) {
+			return fmt.Sprintf("non-finite value %v at position %d", x, i)
+		}
+		if x != 0 {
+			zero = false
+		}
+	}
+	if zero && v.Metric == MetricCosine {
+		return "zero vector is not allowed with the cosine metric"
+	}
+	return ""
+}
+
+// checkSparse returns the reason sparse values are invalid, or an empty string.
+func checkSparse(sv *SparseValues) string {
+	if len(sv.Indices) != len(sv.Values) {
+		return fmt.Sprintf("sparse vector has %d indices but %d values", len(sv.Indices), len(sv.Values))
+	}
+	for i, x := range sv.Values {
+		if math.IsNaN(float64(x)) || math.IsInf(float64(x), 0) {
+			return fmt.Sprintf("non-finite sparse value %v at position %d", x, i)
+		}
+	}
+	return ""
+}
+
+// DiscoverValidator configures c.Validator from the index's dimension and
+// metric as reported by DescribeIndexStats, and returns it.
+func (c *Client) DiscoverValidator(ctx context.Context) (*VectorValidator, error) {
+	stats, err := c.DescribeIndexStats(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Validator = &VectorValidator{
+		Dimension: stats.Dimension,
+		Metric:    Metric(stats.Metric),
+	}
+	return c.Validator, nil
+}
+
+// validateVectors runs the client's validator, if any, over vectors to be upserted.
+func (c *Client) validateVectors(vectors []*Vector) error {
+	if c.Validator == nil {
+		return nil
+	}
+	for _, vec := range vectors {
+		if err := c.Validator.ValidateVector(vec); err != nil {
+			return c.withIndex(err)
+		}
+	}
+	return nil
+}
+
+// validateQuery runs the client's validator, if any, over a query vector.
+func (c *Client) validateQuery(values []float32) error {
+	if c.Validator == nil {
+		return nil
+	}
+	return c.withIndex(c.Validator.ValidateQuery(values))
+}
+
+// withIndex records the client's index host on a validation error.
+func (c *Client) withIndex(err error) error {
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		return err
+	}
+	verr.Index = c.IndexURL
+	if u, perr := url.Parse(c.IndexURL); perr == nil && u.Host != "" {
+		verr.Index = u.Host
+	}
+	return verr
+}
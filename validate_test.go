@@ -0,0 +1,107 @@
+package pinecone
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestVectorValidator(t *testing.T) {
+	v := &VectorValidator{Dimension: 3, Metric: MetricCosine}
+
+	cases := []struct {
+		name   string
+		vec    *Vector
+		reason string
+	}{
+		{"valid", &Vector{ID: "a", Values: []float32{0.1, 0.2, 0.3}}, ""},
+		{"empty_id", &Vector{Values: []float32{0.1, 0.2, 0.3}}, "empty id"},
+		{"wrong_dimension", &Vector{ID: "a", Values: []float32{0.1}}, "dimension 1"},
+		{"nan", &Vector{ID: "a", Values: []float32{0.1, float32(math.NaN()), 0.3}}, "non-finite"},
+		{"inf", &Vector{ID: "a", Values: []float32{float32(math.Inf(1)), 0.2, 0.3}}, "non-finite"},
+		{"zero_on_cosine", &Vector{ID: "a", Values: []float32{0, 0, 0}}, "zero vector"},
+		{"sparse_only", &Vector{ID: "a", SparseValues: &SparseValues{Indices: []uint32{1}, Values: []float32{0.5}}}, ""},
+		{"sparse_length_mismatch", &Vector{ID: "a", SparseValues: &SparseValues{Indices: []uint32{1, 2}, Values: []float32{0.5}}}, "2 indices but 1 values"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := v.ValidateVector(tc.vec)
+			if tc.reason == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.reason) {
+				t.Fatalf("expected %q error, got %v", tc.reason, err)
+			}
+		})
+	}
+
+	t.Run("zero_allowed_on_dotproduct", func(t *testing.T) {
+		dp := &VectorValidator{Dimension: 2, Metric: MetricDotProduct}
+		if err := dp.ValidateQuery([]float32{0, 0}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestClientValidation(t *testing.T) {
+	t.Run("upsert_rejected_before_request", func(t *testing.T) {
+		var called bool
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer s.Close()
+
+		client := NewClient(s.URL, "key")
+		client.Validator = &VectorValidator{Dimension: 2}
+
+		_, err := client.UpsertVectors(context.Background(), []*Vector{
+			{ID: "ok", Values: []float32{0.1, 0.2}},
+			{ID: "bad", Values: []float32{0.1}},
+		}, "ns")
+
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("expected ValidationError, got %v", err)
+		}
+		if verr.VectorID != "bad" || verr.Index != strings.TrimPrefix(s.URL, "http://") {
+			t.Errorf("unexpected error fields: %+v", verr)
+		}
+		if called {
+			t.Error("expected no request to be sent")
+		}
+	})
+
+	t.Run("query_rejected_before_request", func(t *testing.T) {
+		client := NewClient("https://example-index.svc.us-east1-gcp.io", "key")
+		client.Validator = &VectorValidator{Dimension: 2}
+
+		_, err := client.QueryByVector(context.Background(), &QueryByVectorRequest{Vector: []float32{1, 2, 3}, TopK: 1})
+		if err == nil || !strings.Contains(err.Error(), "query vector for index example-index.svc.us-east1-gcp.io") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("discover_from_stats", func(t *testing.T) {
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"dimension":1536,"metric":"cosine","totalVectorCount":0}`))
+		}))
+		defer s.Close()
+
+		client := NewClient(s.URL, "key")
+		v, err := client.DiscoverValidator(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if client.Validator != v || v.Dimension != 1536 || v.Metric != MetricCosine {
+			t.Errorf("unexpected validator: %+v", v)
+		}
+	})
+}
@@ -0,0 +1,562 @@
+package pinecone
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// SyncPolicy controls when a BufferedWriter fsyncs its log.
+type SyncPolicy int
+
+const (
+	// SyncEveryWrite fsyncs after every logged operation, so an operation is
+	// durable once Upsert or Delete returns.
+	SyncEveryWrite SyncPolicy = iota
+
+	// SyncOnFlush fsyncs before each flush to Pinecone. Operations logged
+	// since the last flush may be lost if the machine crashes.
+	SyncOnFlush
+
+	// SyncNever leaves syncing to the operating system.
+	SyncNever
+)
+
+// Defaults for BufferedWriterOptions.
+const (
+	defaultWALBatchSize    = 100
+	defaultWALCompactAfter = 1000
+)
+
+// ErrWriterClosed is returned when writing to a closed writer.
+var ErrWriterClosed = errors.New("pinecone: writer is closed")
+
+// DroppedBatchError reports a batch of a BufferedWriter's operations that
+// Pinecone rejected with a client error, such as a dimension mismatch. Such a
+// batch would fail on every retry, so it is dropped from the log rather than
+// block the operations queued behind it.
+type DroppedBatchError struct {
+	Namespace string
+
+	// Vectors holds the dropped upserts, or IDs the dropped deletes.
+	Vectors []*Vector
+	IDs     []string
+
+	Err error
+}
+
+// Error returns the string representation of the error.
+func (e *DroppedBatchError) Error() string {
+	return fmt.Sprintf("pinecone: dropped batch of %d operations in namespace %q: %v", len(e.Vectors)+len(e.IDs), e.Namespace, e.Err)
+}
+
+// Unwrap returns the error Pinecone rejected the batch with.
+func (e *DroppedBatchError) Unwrap() error {
+	return e.Err
+}
+
+// BufferedWriterOptions configures a BufferedWriter.
+type BufferedWriterOptions struct {
+	// BatchSize is the maximum number of vectors or IDs per request, and the
+	// number of buffered items that triggers a flush. Defaults to 100.
+	BatchSize int
+
+	// FlushInterval, if positive, flushes buffered operations in the
+	// background at this interval.
+	FlushInterval time.Duration
+
+	// Sync controls when the log is fsynced. Defaults to SyncEveryWrite.
+	Sync SyncPolicy
+
+	// CompactAfter rewrites the log without acknowledged operations once this
+	// many have accumulated. Defaults to 1000.
+	CompactAfter int
+
+	// OnError, if set, receives errors from background flushes and from the
+	// synchronous flushes triggered by Upsert and Delete.
+	OnError func(error)
+}
+
+// BufferedWriter buffers upserts and deletes in a local append-only log and
+// flushes them to Pinecone in batches. Operations not yet acknowledged by
+// Pinecone are replayed when the log is reopened, so queued writes survive a
+// process crash.
+//
+// Operations are applied in the order they were logged. Because upserts and
+// deletes by ID are idempotent, an operation interrupted mid-flush is simply
+// sent again. Batches Pinecone rejects with a 4xx status other than 408 and
+// 429 are dropped and reported as a *DroppedBatchError.
+type BufferedWriter struct {
+	c    *Client
+	path string
+	opts BufferedWriterOptions
+
+	// mu guards the log file and the pending queue.
+	mu      sync.Mutex
+	f       *os.File
+	seq     uint64
+	pending []*walEntry
+	items   int
+	acked   int
+	closed  bool
+
+	// flushMu serializes flushes so operations are sent in order.
+	flushMu sync.Mutex
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// walOp identifies the kind of a log record.
+type walOp string
+
+const (
+	walUpsert walOp = "upsert"
+	walDelete walOp = "delete"
+	walAck    walOp = "ack"
+)
+
+// walEntry is a single log record. An ack record acknowledges every
+// operation with a sequence number up to and including its own.
+type walEntry struct {
+	Seq       uint64    `json:"seq"`
+	Op        walOp     `json:"op"`
+	Namespace string    `json:"namespace,omitempty"`
+	Vectors   []*Vector `json:"vectors,omitempty"`
+	IDs       []string  `json:"ids,omitempty"`
+}
+
+// size returns the number of vectors or IDs in the entry.
+func (e *walEntry) size() int {
+	return len(e.Vectors) + len(e.IDs)
+}
+
+// OpenBufferedWriter opens or creates the log at path and returns a writer
+// that flushes through c. Operations left unacknowledged in an existing log
+// are queued for the next flush.
+func OpenBufferedWriter(c *Client, path string, opts *BufferedWriterOptions) (*BufferedWriter, error) {
+	w := &BufferedWriter{c: c, path: path}
+	if opts != nil {
+		w.opts = *opts
+	}
+	if w.opts.BatchSize <= 0 {
+		w.opts.BatchSize = defaultWALBatchSize
+	}
+	if w.opts.CompactAfter <= 0 {
+		w.opts.CompactAfter = defaultWALCompactAfter
+	}
+
+	if err := w.replay(); err != nil {
+		return nil, err
+	}
+	// Rewriting the log on open also drops a record torn by a crash.
+	if err := w.compact(); err != nil {
+		return nil, err
+	}
+
+	if w.opts.FlushInterval > 0 {
+		w.stop = make(chan struct{})
+		w.done = make(chan struct{})
+		go w.loop()
+	}
+	return w, nil
+}
+
+// Upsert logs an upsert of vectors into namespace. It flushes synchronously
+// once BatchSize items are buffered.
+//
+// The vectors are checked by the client's Validator and MetadataValidator,
+// then copied, so the caller may reuse them once Upsert returns.
+//
+// An error means the upsert was not logged and may be retried. Once it is
+// logged, a failed flush leaves it queued for the next flush and is reported
+// to OnError instead, so a retry does not queue it twice.
+func (w *BufferedWriter) Upsert(ctx context.Context, vectors []*Vector, namespace string) error {
+	if len(vectors) == 0 {
+		return nil
+	}
+	if err := w.c.validateVectors(vectors); err != nil {
+		return err
+	}
+	if w.c.MetadataValidator != nil {
+		var err error
+		if vectors, err = w.c.MetadataValidator.Check(vectors); err != nil {
+			return err
+		}
+	}
+
+	copied := make([]*Vector, len(vectors))
+	for i, v := range vectors {
+		copied[i] = cloneVector(v)
+	}
+	return w.append(ctx, &walEntry{Op: walUpsert, Namespace: namespace, Vectors: copied})
+}
+
+// Delete logs a delete of ids from namespace. It flushes synchronously once
+// BatchSize items are buffered. Errors are reported as for Upsert.
+func (w *BufferedWriter) Delete(ctx context.Context, ids []string, namespace string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return w.append(ctx, &walEntry{Op: walDelete, Namespace: namespace, IDs: slices.Clone(ids)})
+}
+
+// Pending returns the number of operations not yet acknowledged by Pinecone.
+func (w *BufferedWriter) Pending() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.pending)
+}
+
+// Flush sends every buffered operation to Pinecone. On error, operations from
+// the failed batch onwards remain buffered and are retried by the next flush.
+// Dropped batches do not stop the flush; their errors are joined and returned
+// once every other operation has been sent.
+func (w *BufferedWriter) Flush(ctx context.Context) error {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	w.mu.Lock()
+	if w.f == nil {
+		w.mu.Unlock()
+		return ErrWriterClosed
+	}
+	entries := append([]*walEntry(nil), w.pending...)
+	var err error
+	if w.opts.Sync == SyncOnFlush && len(entries) > 0 {
+		err = w.f.Sync()
+	}
+	w.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	var b walBatch
+	var dropped []error
+	send := func() error {
+		err := w.send(ctx, &b)
+		if _, ok := err.(*DroppedBatchError); ok {
+			dropped = append(dropped, err)
+			return nil
+		}
+		return err
+	}
+	for _, e := range entries {
+		vectors, ids := e.Vectors, e.IDs
+		for len(vectors)+len(ids) > 0 {
+			if b.n > 0 && (b.op != e.Op || b.namespace != e.Namespace || b.n == w.opts.BatchSize) {
+				if err := send(); err != nil {
+					return err
+				}
+			}
+			b.op, b.namespace = e.Op, e.Namespace
+
+			take := w.opts.BatchSize - b.n
+			if e.Op == walUpsert {
+				take = min(take, len(vectors))
+				b.vectors = append(b.vectors, vectors[:take]...)
+				vectors = vectors[take:]
+			} else {
+				take = min(take, len(ids))
+				b.ids = append(b.ids, ids[:take]...)
+				ids = ids[take:]
+			}
+			b.n += take
+		}
+		// The entry's last items are in the current batch, so it is complete
+		// once that batch is sent.
+		b.ackSeq = e.Seq
+		b.completed++
+	}
+	if b.n > 0 {
+		if err := send(); err != nil {
+			return err
+		}
+	}
+	return errors.Join(dropped...)
+}
+
+// Close stops background flushing, flushes buffered operations and closes the
+// log. Operations that could not be flushed remain in the log for the next
+// OpenBufferedWriter.
+func (w *BufferedWriter) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrWriterClosed
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	if w.stop != nil {
+		close(w.stop)
+		<-w.done
+	}
+
+	err := w.Flush(ctx)
+
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if cerr := w.f.Close(); err == nil {
+		err = cerr
+	}
+	w.f = nil
+	return err
+}
+
+// walBatch accumulates consecutive operations of the same kind and namespace
+// into a single request.
+type walBatch struct {
+	op        walOp
+	namespace string
+	vectors   []*Vector
+	ids       []string
+	n         int
+
+	// ackSeq is the sequence number of the last entry completed by this
+	// batch, and completed the number of entries it completes.
+	ackSeq    uint64
+	completed int
+}
+
+// send issues the batch and acknowledges the entries it completes. A batch
+// rejected for good is acknowledged too, and reported as a *DroppedBatchError.
+func (w *BufferedWriter) send(ctx context.Context, b *walBatch) error {
+	var err error
+	if b.op == walUpsert {
+		_, err = w.c.UpsertVectors(ctx, b.vectors, b.namespace)
+	} else {
+		err = w.c.DeleteVectorsByID(ctx, b.ids, b.namespace)
+	}
+	if err != nil && !permanent(err) {
+		return err
+	}
+	if err != nil {
+		err = &DroppedBatchError{Namespace: b.namespace, Vectors: b.vectors, IDs: b.ids, Err: err}
+	}
+
+	if b.completed > 0 {
+		if ackErr := w.ack(b.ackSeq, b.completed); ackErr != nil {
+			return ackErr
+		}
+	}
+	*b = walBatch{}
+	return err
+}
+
+// permanent reports whether err is a client error that retrying cannot fix.
+func permanent(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	status := apiErr.StatusCode
+	return status >= 400 && status < 500 && status != http.StatusRequestTimeout && status != http.StatusTooManyRequests
+}
+
+// append logs e and queues it, flushing if the buffer is full. Flush errors
+// go to OnError, since e is queued either way.
+func (w *BufferedWriter) append(ctx context.Context, e *walEntry) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrWriterClosed
+	}
+
+	w.seq++
+	e.Seq = w.seq
+	if err := w.writeRecord(e); err != nil {
+		w.mu.Unlock()
+		return err
+	}
+	w.pending = append(w.pending, e)
+	w.items += e.size()
+	full := w.items >= w.opts.BatchSize
+	w.mu.Unlock()
+
+	if full {
+		if err := w.Flush(ctx); err != nil && w.opts.OnError != nil {
+			w.opts.OnError(err)
+		}
+	}
+	return nil
+}
+
+// ack records that the first n pending entries, up to seq, reached Pinecone.
+func (w *BufferedWriter) ack(seq uint64, n int) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.writeRecord(&walEntry{Seq: seq, Op: walAck}); err != nil {
+		return err
+	}
+	for _, e := range w.pending[:n] {
+		w.items -= e.size()
+	}
+	w.pending = w.pending[n:]
+	w.acked += n
+
+	if w.acked >= w.opts.CompactAfter {
+		return w.compactLocked()
+	}
+	return nil
+}
+
+// writeRecord appends a record to the log. The caller must hold w.mu.
+func (w *BufferedWriter) writeRecord(e *walEntry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err := w.f.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	if w.opts.Sync == SyncEveryWrite {
+		return w.f.Sync()
+	}
+	return nil
+}
+
+// replay loads unacknowledged operations from an existing log. A final record
+// without its newline is the torn tail of a write interrupted by a crash and
+// is dropped; any other record that fails to decode means the log is corrupt,
+// and replay fails rather than lose the operations logged after it.
+func (w *BufferedWriter) replay() error {
+	f, err := os.Open(w.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var e walEntry
+		if err := json.Unmarshal(line, &e); err != nil {
+			return fmt.Errorf("pinecone: corrupt record after seq %d in %s: %w", w.seq, w.path, err)
+		}
+		w.seq = max(w.seq, e.Seq)
+
+		if e.Op == walAck {
+			n := 0
+			for n < len(w.pending) && w.pending[n].Seq <= e.Seq {
+				w.items -= w.pending[n].size()
+				n++
+			}
+			w.pending = w.pending[n:]
+			continue
+		}
+		w.pending = append(w.pending, &e)
+		w.items += e.size()
+	}
+}
+
+// compact takes w.mu and rewrites the log.
+func (w *BufferedWriter) compact() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.compactLocked()
+}
+
+// compactLocked atomically replaces the log with one holding only the pending
+// operations, then reopens it for appending. The caller must hold w.mu.
+func (w *BufferedWriter) compactLocked() error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range w.pending {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	if err := writeFileAtomic(w.path, buf.Bytes()); err != nil {
+		return err
+	}
+
+	var err error
+	if w.f != nil {
+		w.f.Close()
+	}
+	if w.f, err = os.OpenFile(w.path, os.O_APPEND|os.O_WRONLY, 0o644); err != nil {
+		return err
+	}
+	w.acked = 0
+	return nil
+}
+
+// loop flushes at the configured interval until the writer is closed.
+func (w *BufferedWriter) loop() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.opts.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			if err := w.Flush(context.Background()); err != nil && w.opts.OnError != nil {
+				w.opts.OnError(err)
+			}
+		}
+	}
+}
+
+// syncDir fsyncs a directory so a rename within it is durable. Errors are
+// ignored because not every platform supports syncing directories.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
+
+// writeFileAtomic replaces path with data via a synced temporary file, so a
+// crash leaves either the old or the new contents.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	syncDir(filepath.Dir(path))
+	return nil
+}
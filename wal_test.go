@@ -0,0 +1,305 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// walRequest is a request observed by walTestServer.
+type walRequest struct {
+	path      string
+	namespace string
+	ids       []string
+}
+
+// walTestServer records upserts and deletes, failing while *fail is set.
+func walTestServer(t *testing.T, mu *sync.Mutex, got *[]walRequest, fail *bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if *fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var body struct {
+			Namespace string    `json:"namespace"`
+			IDs       []string  `json:"ids"`
+			Vectors   []*Vector `json:"vectors"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		req := walRequest{path: r.URL.Path, namespace: body.Namespace, ids: body.IDs}
+		for _, v := range body.Vectors {
+			req.ids = append(req.ids, v.ID)
+		}
+		*got = append(*got, req)
+
+		if r.URL.Path == "/vectors/upsert" {
+			json.NewEncoder(w).Encode(UpsertResponse{UpsertedCount: uint32(len(body.Vectors))})
+		}
+	}))
+}
+
+func vectorsWithIDs(ids ...string) []*Vector {
+	out := make([]*Vector, len(ids))
+	for i, id := range ids {
+		out[i] = &Vector{ID: id, Values: []float32{1}}
+	}
+	return out
+}
+
+func TestBufferedWriter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("batches_in_order", func(t *testing.T) {
+		var mu sync.Mutex
+		var got []walRequest
+		var fail bool
+		s := walTestServer(t, &mu, &got, &fail)
+		defer s.Close()
+
+		w, err := OpenBufferedWriter(NewClient(s.URL, "key"), filepath.Join(t.TempDir(), "wal.log"), &BufferedWriterOptions{BatchSize: 3})
+		if err != nil {
+			t.Fatalf("open failed: %v", err)
+		}
+
+		w.Upsert(ctx, vectorsWithIDs("a", "b"), "ns")
+		w.Delete(ctx, []string{"a"}, "ns")
+		w.Upsert(ctx, vectorsWithIDs("c", "d", "e", "f"), "ns")
+		w.Upsert(ctx, vectorsWithIDs("g"), "other")
+		if err := w.Close(ctx); err != nil {
+			t.Fatalf("close failed: %v", err)
+		}
+
+		want := []string{
+			"/vectors/upsert ns a,b",
+			"/vectors/delete ns a",
+			"/vectors/upsert ns c,d,e",
+			"/vectors/upsert ns f",
+			"/vectors/upsert other g",
+		}
+		if len(got) != len(want) {
+			t.Fatalf("expected %d requests, got %+v", len(want), got)
+		}
+		for i, r := range got {
+			if s := r.path + " " + r.namespace + " " + strings.Join(r.ids, ","); s != want[i] {
+				t.Errorf("request %d: expected %q, got %q", i, want[i], s)
+			}
+		}
+	})
+
+	t.Run("replays_after_crash", func(t *testing.T) {
+		var mu sync.Mutex
+		var got []walRequest
+		fail := true
+		s := walTestServer(t, &mu, &got, &fail)
+		defer s.Close()
+
+		path := filepath.Join(t.TempDir(), "wal.log")
+		client := NewClient(s.URL, "key")
+
+		var flushErr error
+		w, err := OpenBufferedWriter(client, path, &BufferedWriterOptions{BatchSize: 2, OnError: func(err error) { flushErr = err }})
+		if err != nil {
+			t.Fatalf("open failed: %v", err)
+		}
+		w.Upsert(ctx, vectorsWithIDs("a"), "ns")
+		if err := w.Upsert(ctx, vectorsWithIDs("b"), "ns"); err != nil {
+			t.Fatalf("expected a logged upsert to succeed despite the failed flush, got %v", err)
+		}
+		if flushErr == nil || w.Pending() != 2 {
+			t.Fatalf("expected the flush error to be reported and both upserts queued, got %v and %d", flushErr, w.Pending())
+		}
+
+		// Simulate a crash mid-write: abandon the writer and tear the log's tail.
+		w.f.Close()
+		f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+		f.WriteString(`{"seq":3,"op":"ups`)
+		f.Close()
+
+		mu.Lock()
+		fail = false
+		mu.Unlock()
+
+		w, err = OpenBufferedWriter(client, path, nil)
+		if err != nil {
+			t.Fatalf("reopen failed: %v", err)
+		}
+		if w.Pending() != 2 {
+			t.Fatalf("expected 2 replayed operations, got %d", w.Pending())
+		}
+		if err := w.Close(ctx); err != nil {
+			t.Fatalf("close failed: %v", err)
+		}
+		if len(got) != 1 || strings.Join(got[0].ids, ",") != "a,b" {
+			t.Errorf("expected replayed upsert of a,b, got %+v", got)
+		}
+
+		w, err = OpenBufferedWriter(client, path, nil)
+		if err != nil {
+			t.Fatalf("reopen failed: %v", err)
+		}
+		defer w.Close(ctx)
+		if w.Pending() != 0 {
+			t.Errorf("expected acknowledged operations not to replay, got %d", w.Pending())
+		}
+	})
+
+	t.Run("corrupt_record", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "wal.log")
+		log := `{"seq":1,"op":"upsert","namespace":"ns","vectors":[{"id":"a","values":[1]}]}
+{"seq":2,"op":"ups
+{"seq":3,"op":"upsert","namespace":"ns","vectors":[{"id":"c","values":[1]}]}
+`
+		os.WriteFile(path, []byte(log), 0o644)
+
+		if _, err := OpenBufferedWriter(NewClient("http://localhost", "key"), path, nil); err == nil {
+			t.Fatal("expected a corrupt record before the tail to fail the open")
+		}
+		if data, _ := os.ReadFile(path); string(data) != log {
+			t.Errorf("expected the corrupt log to be left untouched, got %q", data)
+		}
+	})
+
+	t.Run("drops_rejected_batches", func(t *testing.T) {
+		var mu sync.Mutex
+		var sent []string
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body UpsertRequest
+			json.NewDecoder(r.Body).Decode(&body)
+			if body.Namespace == "a" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"message":"dimension mismatch"}`))
+				return
+			}
+			mu.Lock()
+			sent = append(sent, body.Namespace)
+			mu.Unlock()
+			json.NewEncoder(w).Encode(UpsertResponse{UpsertedCount: uint32(len(body.Vectors))})
+		}))
+		defer s.Close()
+
+		w, err := OpenBufferedWriter(NewClient(s.URL, "key"), filepath.Join(t.TempDir(), "wal.log"), &BufferedWriterOptions{BatchSize: 10})
+		if err != nil {
+			t.Fatalf("open failed: %v", err)
+		}
+		defer w.Close(ctx)
+		w.Upsert(ctx, vectorsWithIDs("bad"), "a")
+		w.Upsert(ctx, vectorsWithIDs("good"), "b")
+
+		err = w.Flush(ctx)
+		var dropped *DroppedBatchError
+		if !errors.As(err, &dropped) || dropped.Namespace != "a" || dropped.Vectors[0].ID != "bad" {
+			t.Fatalf("expected the rejected batch to be reported, got %v", err)
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected the API error to be wrapped, got %v", err)
+		}
+		if w.Pending() != 0 || len(sent) != 1 || sent[0] != "b" {
+			t.Errorf("expected the good upsert to be sent past the rejected one, got %d pending and %v", w.Pending(), sent)
+		}
+	})
+
+	t.Run("validates_and_copies", func(t *testing.T) {
+		var mu sync.Mutex
+		var got []walRequest
+		var fail bool
+		s := walTestServer(t, &mu, &got, &fail)
+		defer s.Close()
+
+		c := NewClient(s.URL, "key")
+		c.Validator = &VectorValidator{Dimension: 1}
+		w, err := OpenBufferedWriter(c, filepath.Join(t.TempDir(), "wal.log"), &BufferedWriterOptions{BatchSize: 10})
+		if err != nil {
+			t.Fatalf("open failed: %v", err)
+		}
+		defer w.Close(ctx)
+
+		if err := w.Upsert(ctx, []*Vector{{ID: "wide", Values: []float32{1, 2}}}, "ns"); err == nil {
+			t.Error("expected a vector of the wrong dimension to be refused")
+		}
+		vectors := vectorsWithIDs("a")
+		w.Upsert(ctx, vectors, "ns")
+		vectors[0].ID = "mutated"
+		if w.Pending() != 1 {
+			t.Fatalf("expected only the valid upsert to be logged, got %d", w.Pending())
+		}
+		if err := w.Flush(ctx); err != nil {
+			t.Fatalf("flush failed: %v", err)
+		}
+		if len(got) != 1 || got[0].ids[0] != "a" {
+			t.Errorf("expected the vector as it was upserted, got %+v", got)
+		}
+	})
+
+	t.Run("compaction", func(t *testing.T) {
+		var mu sync.Mutex
+		var got []walRequest
+		var fail bool
+		s := walTestServer(t, &mu, &got, &fail)
+		defer s.Close()
+
+		path := filepath.Join(t.TempDir(), "wal.log")
+		w, err := OpenBufferedWriter(NewClient(s.URL, "key"), path, &BufferedWriterOptions{BatchSize: 1, CompactAfter: 2})
+		if err != nil {
+			t.Fatalf("open failed: %v", err)
+		}
+		defer w.Close(ctx)
+
+		for _, id := range []string{"a", "b"} {
+			if err := w.Upsert(ctx, vectorsWithIDs(id), "ns"); err != nil {
+				t.Fatalf("upsert failed: %v", err)
+			}
+		}
+
+		data, _ := os.ReadFile(path)
+		if len(data) != 0 {
+			t.Errorf("expected compacted log to be empty, got %q", data)
+		}
+	})
+
+	t.Run("background_flush", func(t *testing.T) {
+		var mu sync.Mutex
+		var got []walRequest
+		var fail bool
+		s := walTestServer(t, &mu, &got, &fail)
+		defer s.Close()
+
+		w, err := OpenBufferedWriter(NewClient(s.URL, "key"), filepath.Join(t.TempDir(), "wal.log"), &BufferedWriterOptions{FlushInterval: 5 * time.Millisecond})
+		if err != nil {
+			t.Fatalf("open failed: %v", err)
+		}
+		defer w.Close(ctx)
+
+		w.Upsert(ctx, vectorsWithIDs("a"), "ns")
+		deadline := time.Now().Add(time.Second)
+		for w.Pending() > 0 && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		if w.Pending() != 0 {
+			t.Fatal("expected background flush")
+		}
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		w, err := OpenBufferedWriter(NewClient("http://localhost", "key"), filepath.Join(t.TempDir(), "wal.log"), nil)
+		if err != nil {
+			t.Fatalf("open failed: %v", err)
+		}
+		w.Close(ctx)
+		if err := w.Upsert(ctx, vectorsWithIDs("a"), "ns"); err != ErrWriterClosed {
+			t.Errorf("expected ErrWriterClosed, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,281 @@
+package pinecone
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Defaults for WriterOptions.
+const (
+	defaultWriterBatchSize   = 100
+	defaultWriterBatchBytes  = 2 << 20
+	defaultWriterMaxInFlight = 4
+)
+
+// WriterOptions configures a Writer.
+type WriterOptions struct {
+	// MaxBatchSize is the number of vectors that triggers a flush of a
+	// namespace's batch. Defaults to 100.
+	MaxBatchSize int
+
+	// MaxBatchBytes is the encoded request size that triggers a flush.
+	// Defaults to 2 MiB, Pinecone's upsert request limit.
+	MaxBatchBytes int
+
+	// Linger, if positive, flushes a batch this long after its first vector
+	// was added, even if it is not full.
+	Linger time.Duration
+
+	// MaxInFlight limits concurrent upsert requests. When that many are in
+	// flight, Add blocks until one completes. Defaults to 4.
+	MaxInFlight int
+
+	// OnError, if set, is called with every failed batch. Otherwise errors are
+	// collected and returned by the next Flush or Close.
+	OnError func(err error, namespace string, vectors []*Vector)
+}
+
+// Writer accumulates vectors into per-namespace batches and upserts them in
+// the background, so streaming ingestion can add one record at a time without
+// issuing one request per record.
+//
+// A Writer is safe for concurrent use. Vectors must not be modified after they
+// are added.
+type Writer struct {
+	c    *Client
+	opts WriterOptions
+
+	mu      sync.Mutex
+	batches map[string]*writerBatch
+	scratch []byte
+	errs    []error
+	closed  bool
+
+	// pending counts batches detached but not yet finished; drained is
+	// closed when it drops to zero.
+	pending int
+	drained chan struct{}
+
+	sem chan struct{}
+}
+
+// writerBatch is a namespace's batch under construction.
+type writerBatch struct {
+	namespace string
+	vectors   []*Vector
+	bytes     int
+	timer     *time.Timer
+}
+
+// upsertOverhead approximates the encoded size of an upsert request without
+// its vectors.
+const upsertOverhead = len(`{"vectors":[],"namespace":""}`)
+
+// NewWriter returns a Writer that upserts through c.
+func NewWriter(c *Client, opts *WriterOptions) *Writer {
+	w := &Writer{c: c, batches: make(map[string]*writerBatch)}
+	if opts != nil {
+		w.opts = *opts
+	}
+	if w.opts.MaxBatchSize <= 0 {
+		w.opts.MaxBatchSize = defaultWriterBatchSize
+	}
+	if w.opts.MaxBatchBytes <= 0 {
+		w.opts.MaxBatchBytes = defaultWriterBatchBytes
+	}
+	if w.opts.MaxInFlight <= 0 {
+		w.opts.MaxInFlight = defaultWriterMaxInFlight
+	}
+	w.sem = make(chan struct{}, w.opts.MaxInFlight)
+	return w
+}
+
+// Add queues v for upsert into namespace. It blocks while a full batch waits
+// for a free request slot, returning early with the context's error if ctx is
+// done; the vector remains queued in that case.
+func (w *Writer) Add(ctx context.Context, v *Vector, namespace string) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrWriterClosed
+	}
+
+	var err error
+	w.scratch, err = v.appendJSON(w.scratch[:0])
+	if err != nil {
+		w.mu.Unlock()
+		return err
+	}
+	ready := w.push(v, namespace, len(w.scratch)+1)
+	w.mu.Unlock()
+
+	return w.ship(ctx, ready)
+}
+
+// Flush sends every queued batch and waits for all in-flight requests. It
+// returns the errors collected since the last Flush when OnError is not set.
+func (w *Writer) Flush(ctx context.Context) error {
+	w.mu.Lock()
+	ready := make([]*writerBatch, 0, len(w.batches))
+	for _, b := range w.batches {
+		ready = append(ready, w.detach(b))
+	}
+	w.mu.Unlock()
+
+	if err := w.ship(ctx, ready); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	if w.pending > 0 {
+		drained := w.drained
+		w.mu.Unlock()
+		select {
+		case <-drained:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		w.mu.Lock()
+	}
+	defer w.mu.Unlock()
+	err := errors.Join(w.errs...)
+	w.errs = nil
+	return err
+}
+
+// Close stops accepting vectors, then flushes and drains everything queued.
+func (w *Writer) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrWriterClosed
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	return w.Flush(ctx)
+}
+
+// push appends v, whose encoded size is size, to namespace's open batch,
+// opening one if needed, and returns the batches it filled, detached. The
+// caller must hold w.mu.
+func (w *Writer) push(v *Vector, namespace string, size int) []*writerBatch {
+	var ready []*writerBatch
+	b := w.batches[namespace]
+	if b != nil && b.bytes+size > w.opts.MaxBatchBytes {
+		ready = append(ready, w.detach(b))
+		b = nil
+	}
+	if b == nil {
+		b = &writerBatch{namespace: namespace, bytes: upsertOverhead + len(namespace)}
+		w.batches[namespace] = b
+		if w.opts.Linger > 0 {
+			b.timer = time.AfterFunc(w.opts.Linger, func() { w.linger(b) })
+		}
+	}
+	b.vectors = append(b.vectors, v)
+	b.bytes += size
+	if len(b.vectors) >= w.opts.MaxBatchSize || b.bytes >= w.opts.MaxBatchBytes {
+		ready = append(ready, w.detach(b))
+	}
+	return ready
+}
+
+// detach removes b from the open batches and counts it as pending until it
+// is sent or requeued. The caller must hold w.mu.
+func (w *Writer) detach(b *writerBatch) *writerBatch {
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	delete(w.batches, b.namespace)
+	if w.pending == 0 {
+		w.drained = make(chan struct{})
+	}
+	w.pending++
+	return b
+}
+
+// release marks a pending batch as finished. The caller must hold w.mu.
+func (w *Writer) release() {
+	w.pending--
+	if w.pending == 0 {
+		close(w.drained)
+	}
+}
+
+// linger flushes b when its linger time expires, unless it was already sent.
+func (w *Writer) linger(b *writerBatch) {
+	w.mu.Lock()
+	if w.batches[b.namespace] != b {
+		w.mu.Unlock()
+		return
+	}
+	w.detach(b)
+	w.mu.Unlock()
+
+	w.ship(context.Background(), []*writerBatch{b})
+}
+
+// ship starts an upsert for each batch once a request slot is free. If ctx is
+// done first, the unsent batches are requeued and the context's error returned.
+func (w *Writer) ship(ctx context.Context, batches []*writerBatch) error {
+	for i, b := range batches {
+		select {
+		case w.sem <- struct{}{}:
+		case <-ctx.Done():
+			w.requeue(batches[i:])
+			return ctx.Err()
+		}
+
+		go func() {
+			_, err := w.c.UpsertVectors(context.Background(), b.vectors, b.namespace)
+			<-w.sem
+			if err != nil && w.opts.OnError != nil {
+				w.opts.OnError(err, b.namespace, b.vectors)
+			}
+
+			w.mu.Lock()
+			defer w.mu.Unlock()
+			if err != nil && w.opts.OnError == nil {
+				w.errs = append(w.errs, err)
+			}
+			w.release()
+		}()
+	}
+	return nil
+}
+
+// requeue puts unsent batches back in front of any vectors added since,
+// re-batching them against the size limits with a fresh linger timer. Batches
+// the merge fills are shipped in the background.
+func (w *Writer) requeue(batches []*writerBatch) {
+	w.mu.Lock()
+	var ready []*writerBatch
+	for _, b := range batches {
+		vectors := b.vectors
+		if cur := w.batches[b.namespace]; cur != nil {
+			if cur.timer != nil {
+				cur.timer.Stop()
+			}
+			delete(w.batches, b.namespace)
+			vectors = append(vectors, cur.vectors...)
+		}
+		for _, v := range vectors {
+			// Every vector was encoded once already, so this cannot fail.
+			w.scratch, _ = v.appendJSON(w.scratch[:0])
+			ready = append(ready, w.push(v, b.namespace, len(w.scratch)+1)...)
+		}
+	}
+	// Release only after pushing, so pending cannot briefly reach zero while
+	// the batches just filled are still to be shipped.
+	for range batches {
+		w.release()
+	}
+	w.mu.Unlock()
+
+	if len(ready) > 0 {
+		go w.ship(context.Background(), ready)
+	}
+}
@@ -0,0 +1,226 @@
+package pinecone
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingClient returns an HTTP client whose requests wait until block is closed.
+func blockingClient(block <-chan struct{}) *http.Client {
+	return &http.Client{Transport: roundTripFunc(func(req *http.Request) *http.Response {
+		<-block
+		resp, err := http.DefaultTransport.RoundTrip(req)
+		if err != nil {
+			panic(err)
+		}
+		return resp
+	})}
+}
+
+func TestWriter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("batches_by_size_and_namespace", func(t *testing.T) {
+		var mu sync.Mutex
+		var got []walRequest
+		var fail bool
+		s := walTestServer(t, &mu, &got, &fail)
+		defer s.Close()
+
+		w := NewWriter(NewClient(s.URL, "key"), &WriterOptions{MaxBatchSize: 2})
+		for _, v := range vectorsWithIDs("a", "b", "c") {
+			if err := w.Add(ctx, v, "ns"); err != nil {
+				t.Fatalf("add failed: %v", err)
+			}
+		}
+		w.Add(ctx, vectorsWithIDs("x")[0], "other")
+		if err := w.Close(ctx); err != nil {
+			t.Fatalf("close failed: %v", err)
+		}
+
+		counts := map[string]int{}
+		for _, r := range got {
+			counts[r.namespace] += len(r.ids)
+			if len(r.ids) > 2 {
+				t.Errorf("batch of %d exceeds MaxBatchSize", len(r.ids))
+			}
+		}
+		if len(got) != 3 || counts["ns"] != 3 || counts["other"] != 1 {
+			t.Errorf("unexpected requests: %+v", got)
+		}
+
+		if err := w.Add(ctx, vectorsWithIDs("d")[0], "ns"); err != ErrWriterClosed {
+			t.Errorf("expected ErrWriterClosed, got %v", err)
+		}
+	})
+
+	t.Run("flushes_on_bytes", func(t *testing.T) {
+		var mu sync.Mutex
+		var got []walRequest
+		var fail bool
+		s := walTestServer(t, &mu, &got, &fail)
+		defer s.Close()
+
+		w := NewWriter(NewClient(s.URL, "key"), &WriterOptions{MaxBatchBytes: 80})
+		for _, v := range vectorsWithIDs("a", "b", "c", "d") {
+			w.Add(ctx, v, "ns")
+		}
+		if err := w.Close(ctx); err != nil {
+			t.Fatalf("close failed: %v", err)
+		}
+
+		total := 0
+		for _, r := range got {
+			total += len(r.ids)
+		}
+		if len(got) < 2 || total != 4 {
+			t.Errorf("expected several small batches holding 4 vectors, got %+v", got)
+		}
+	})
+
+	t.Run("flushes_on_linger", func(t *testing.T) {
+		var mu sync.Mutex
+		var got []walRequest
+		var fail bool
+		s := walTestServer(t, &mu, &got, &fail)
+		defer s.Close()
+
+		w := NewWriter(NewClient(s.URL, "key"), &WriterOptions{Linger: 10 * time.Millisecond})
+		defer w.Close(ctx)
+		w.Add(ctx, vectorsWithIDs("a")[0], "ns")
+
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			mu.Lock()
+			n := len(got)
+			mu.Unlock()
+			if n == 1 {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("batch was not flushed after linger")
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	})
+
+	t.Run("backpressure", func(t *testing.T) {
+		var mu sync.Mutex
+		var got []walRequest
+		var fail bool
+		block := make(chan struct{})
+		s := walTestServer(t, &mu, &got, &fail)
+		defer s.Close()
+		c := NewClient(s.URL, "key")
+		c.HTTPClient = blockingClient(block)
+
+		w := NewWriter(c, &WriterOptions{MaxBatchSize: 1, MaxInFlight: 1})
+		if err := w.Add(ctx, vectorsWithIDs("a")[0], "ns"); err != nil {
+			t.Fatalf("first add failed: %v", err)
+		}
+
+		short, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		if err := w.Add(short, vectorsWithIDs("b")[0], "ns"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected add to block until the deadline, got %v", err)
+		}
+
+		close(block)
+		if err := w.Close(ctx); err != nil {
+			t.Fatalf("close failed: %v", err)
+		}
+		if len(got) != 2 {
+			t.Errorf("expected the blocked vector to be sent on close, got %+v", got)
+		}
+	})
+
+	t.Run("requeued_batch_lingers", func(t *testing.T) {
+		var mu sync.Mutex
+		var got []walRequest
+		var fail bool
+		block := make(chan struct{})
+		s := walTestServer(t, &mu, &got, &fail)
+		defer s.Close()
+		c := NewClient(s.URL, "key")
+		c.HTTPClient = blockingClient(block)
+
+		w := NewWriter(c, &WriterOptions{MaxBatchSize: 3, MaxInFlight: 1, Linger: 200 * time.Millisecond})
+		defer w.Close(ctx)
+		for _, v := range vectorsWithIDs("a", "b", "c", "d", "e") {
+			w.Add(ctx, v, "ns")
+		}
+
+		// The flush detaches d and e but cannot send them before its deadline,
+		// while f and g open a new batch behind them.
+		short, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		flushed := make(chan error)
+		go func() { flushed <- w.Flush(short) }()
+		time.Sleep(10 * time.Millisecond)
+		for _, v := range vectorsWithIDs("f", "g") {
+			w.Add(ctx, v, "ns")
+		}
+		if err := <-flushed; !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected the flush to time out, got %v", err)
+		}
+		close(block)
+
+		// The merged d, e, f, g splits into a full batch and a remainder that
+		// is sent once its linger expires, without another flush.
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			mu.Lock()
+			n := len(got)
+			mu.Unlock()
+			if n == 3 {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("requeued batch was not flushed after linger, got %+v", got)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		for _, r := range got {
+			if len(r.ids) > 3 {
+				t.Errorf("batch of %d exceeds MaxBatchSize", len(r.ids))
+			}
+		}
+		if last := got[2].ids; len(last) != 1 || last[0] != "g" {
+			t.Errorf("expected g to linger alone, got %v", last)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var mu sync.Mutex
+		var got []walRequest
+		fail := true
+		s := walTestServer(t, &mu, &got, &fail)
+		defer s.Close()
+
+		w := NewWriter(NewClient(s.URL, "key"), nil)
+		w.Add(ctx, vectorsWithIDs("a")[0], "ns")
+		if err := w.Close(ctx); err == nil {
+			t.Error("expected close to return the upsert error")
+		}
+
+		var failed []string
+		w = NewWriter(NewClient(s.URL, "key"), &WriterOptions{
+			OnError: func(err error, namespace string, vectors []*Vector) {
+				for _, v := range vectors {
+					failed = append(failed, namespace+"/"+v.ID)
+				}
+			},
+		})
+		w.Add(ctx, vectorsWithIDs("b")[0], "ns")
+		if err := w.Close(ctx); err != nil {
+			t.Errorf("expected errors to go to OnError, got %v", err)
+		}
+		if len(failed) != 1 || failed[0] != "ns/b" {
+			t.Errorf("unexpected OnError calls: %v", failed)
+		}
+	})
+}